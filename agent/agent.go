@@ -0,0 +1,244 @@
+// Package agent 提供自动执行工具调用的 Agent 循环。
+// Runner 会调用模型、执行模型请求的工具、将结果以 tool 消息反馈给模型，直到模型给出最终回答或达到最大轮数。
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// ErrMaxIterations 表示模型在最大轮数内没有给出最终回答
+var ErrMaxIterations = errors.New("agent: max iterations exceeded")
+
+// ErrRejected 表示工具调用被审批钩子拒绝，可以在 Approve 中包装该错误说明原因
+var ErrRejected = errors.New("agent: tool call rejected")
+
+// Hooks 结构体定义了 Agent 循环中的钩子，所有钩子都是可选的。
+// 同一轮中的工具调用并发执行，Approve 与 OnToolResult 可能被多个协程同时调用。
+type Hooks struct {
+	// Approve 在执行工具调用前调用，返回错误时跳过该调用并将错误反馈给模型
+	Approve func(ctx context.Context, call request.ToolCall) error
+	// OnMessage 在每次收到模型回复后调用
+	OnMessage func(ctx context.Context, msg response.Message)
+	// OnToolResult 在每次工具调用完成后调用，err 为工具执行或审批的错误
+	OnToolResult func(ctx context.Context, call request.ToolCall, result string, err error)
+}
+
+// Options 结构体定义了 Runner 的选项
+type Options struct {
+	MaxIterations  int              // 最多调用模型的次数，默认为 10
+	ToolTimeout    time.Duration    // 单个工具调用的超时时间，0 表示不限制
+	Hooks          Hooks            // 钩子
+	RequestOptions []request.Option // 每次请求使用的选项，如模型与温度
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// WithMaxIterations 设置最多调用模型的次数
+func WithMaxIterations(n int) Option {
+	return func(o *Options) { o.MaxIterations = n }
+}
+
+// WithToolTimeout 设置单个工具调用的超时时间
+func WithToolTimeout(d time.Duration) Option {
+	return func(o *Options) { o.ToolTimeout = d }
+}
+
+// WithHooks 设置钩子
+func WithHooks(hooks Hooks) Option {
+	return func(o *Options) { o.Hooks = hooks }
+}
+
+// WithRequestOptions 设置每次请求使用的选项
+func WithRequestOptions(opts ...request.Option) Option {
+	return func(o *Options) { o.RequestOptions = append(o.RequestOptions, opts...) }
+}
+
+// Result 结构体定义了 Agent 循环的结果
+type Result struct {
+	Message    response.Message   // 模型的最终回答
+	Messages   []request.Messages // 完整的消息记录，包含输入、模型回复与工具消息
+	Usage      response.Usage     // 所有模型调用的用量统计之和
+	Iterations int                // 调用模型的次数
+}
+
+// Runner 是自动执行工具调用的 Agent
+type Runner struct {
+	c     uniai.Completer
+	opts  Options
+	tools map[string]Tool
+	order []string // 工具的注册顺序，保证请求中的工具列表稳定
+}
+
+// New 创建一个基于指定客户端的 Runner
+func New(c uniai.Completer, opts ...Option) *Runner {
+	o := Options{MaxIterations: 10}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Runner{c: c, opts: o, tools: make(map[string]Tool)}
+}
+
+// Register 注册工具，同名工具会被覆盖
+func (r *Runner) Register(tools ...Tool) *Runner {
+	for _, t := range tools {
+		if _, ok := r.tools[t.Name]; !ok {
+			r.order = append(r.order, t.Name)
+		}
+		r.tools[t.Name] = t
+	}
+	return r
+}
+
+// Run 从给定的消息开始运行 Agent 循环，直到模型给出不包含工具调用的回答。
+// 同一轮中的多个工具调用会并发执行，结果按调用顺序反馈给模型。
+// 超过最大轮数时返回 ErrMaxIterations，此时 Result 中包含已产生的消息记录。
+func (r *Runner) Run(ctx context.Context, messages []request.Messages) (*Result, error) {
+	result := &Result{Messages: append([]request.Messages(nil), messages...)}
+
+	definitions := make([]request.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		definitions = append(definitions, r.tools[name].definition())
+	}
+
+	for result.Iterations < r.opts.MaxIterations {
+		result.Iterations++
+
+		in := *request.NewRequest()
+		for _, opt := range r.opts.RequestOptions {
+			opt(&in)
+		}
+		in.Messages = result.Messages
+		if len(definitions) > 0 {
+			in.Tools = definitions
+		}
+
+		msg, usage, err := r.complete(ctx, in)
+		if err != nil {
+			return result, err
+		}
+		if usage != nil {
+			result.Usage.PromptTokens += usage.PromptTokens
+			result.Usage.CompletionTokens += usage.CompletionTokens
+			result.Usage.TotalTokens += usage.TotalTokens
+		}
+		if r.opts.Hooks.OnMessage != nil {
+			r.opts.Hooks.OnMessage(ctx, msg)
+		}
+
+		assistant := request.NewAssistantMessage(msg.Content)
+		for _, call := range msg.ToolCalls {
+			assistant.ToolCalls = append(assistant.ToolCalls, request.ToolCall{
+				ID:       call.ID,
+				Type:     call.Type,
+				Function: request.FunctionCall{Name: call.Function.Name, Arguments: call.Function.Arguments},
+			})
+		}
+		result.Messages = append(result.Messages, assistant)
+		result.Message = msg
+
+		if len(assistant.ToolCalls) == 0 {
+			return result, nil
+		}
+		result.Messages = append(result.Messages, r.execute(ctx, assistant.ToolCalls)...)
+	}
+
+	return result, ErrMaxIterations
+}
+
+// complete 调用模型并归并回复
+func (r *Runner) complete(ctx context.Context, in request.Request) (response.Message, *response.Usage, error) {
+	out, err := r.c.Completions(ctx, in)
+	if err != nil {
+		return response.Message{}, nil, err
+	}
+
+	acc := response.NewAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			return response.Message{}, nil, ctx.Err()
+		case item, ok := <-out:
+			if !ok {
+				return acc.Message(), acc.Usage(), acc.Err()
+			}
+			acc.Add(item)
+		}
+	}
+}
+
+// execute 并发执行工具调用，返回按调用顺序排列的工具消息
+func (r *Runner) execute(ctx context.Context, calls []request.ToolCall) []request.Messages {
+	out := make([]request.Messages, len(calls))
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call request.ToolCall) {
+			defer wg.Done()
+
+			result, err := r.call(ctx, call)
+			if r.opts.Hooks.OnToolResult != nil {
+				r.opts.Hooks.OnToolResult(ctx, call, result, err)
+			}
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			out[i] = request.NewToolMessage(call.ID, result)
+		}(i, call)
+	}
+	wg.Wait()
+	return out
+}
+
+// call 执行单个工具调用，处理审批、超时与 panic
+func (r *Runner) call(ctx context.Context, call request.ToolCall) (string, error) {
+	tool, ok := r.tools[call.Function.Name]
+	if !ok {
+		return "", errors.Errorf("unknown tool %q", call.Function.Name)
+	}
+
+	if r.opts.Hooks.Approve != nil {
+		if err := r.opts.Hooks.Approve(ctx, call); err != nil {
+			return "", err
+		}
+	}
+
+	if r.opts.ToolTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.ToolTimeout)
+		defer cancel()
+	}
+
+	// 工具在独立的协程中执行，超时后立即返回，工具应当响应 ctx 的取消以尽快退出。
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- outcome{err: fmt.Errorf("tool %s panicked: %v", call.Function.Name, v)}
+			}
+		}()
+		result, err := tool.Handler(ctx, call.Function.Arguments)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return "", errors.Wrapf(ctx.Err(), "tool %s", call.Function.Name)
+	}
+}
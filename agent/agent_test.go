@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// script 按顺序返回预设回复的客户端
+type script struct {
+	replies  []response.Message
+	requests []request.Request
+}
+
+func (s *script) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	s.requests = append(s.requests, in)
+	msg := s.replies[0]
+	s.replies = s.replies[1:]
+
+	out := make(chan response.Response, 1)
+	out <- response.Response{Choices: []response.Choices{{Message: &msg}}, Usage: &response.Usage{TotalTokens: 1}}
+	close(out)
+	return out, nil
+}
+
+func toolCall(id, name, arguments string) response.ToolCall {
+	return response.ToolCall{ID: id, Type: request.ToolTypeFunction, Function: response.FunctionCall{Name: name, Arguments: arguments}}
+}
+
+type weatherArgs struct {
+	City string `json:"city" description:"城市名称"`
+}
+
+func Test_Run(t *testing.T) {
+	s := &script{replies: []response.Message{
+		{Role: "assistant", ToolCalls: []response.ToolCall{
+			toolCall("1", "weather", `{"city":"北京"}`),
+			toolCall("2", "weather", `{"town":"上海"}`),
+			toolCall("3", "slow", `{}`),
+			toolCall("4", "weather", `{"city":"深圳"}`),
+			toolCall("5", "weather", `{"city":"杭州"}`),
+		}},
+		{Role: "assistant", Content: "晴"},
+	}}
+
+	var running, peak int32
+	weather := NewTool("weather", "查询天气", func(ctx context.Context, args weatherArgs) (any, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			if p := atomic.LoadInt32(&peak); n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return map[string]string{"city": args.City, "weather": "晴"}, nil
+	})
+	slow := NewTool("slow", "", func(ctx context.Context, _ struct{}) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	var rejected []string
+	r := New(s, WithToolTimeout(300*time.Millisecond), WithHooks(Hooks{
+		Approve: func(ctx context.Context, call request.ToolCall) error {
+			if strings.Contains(call.Function.Arguments, "深圳") {
+				rejected = append(rejected, call.ID)
+				return ErrRejected
+			}
+			return nil
+		},
+	})).Register(weather, slow)
+
+	result, err := r.Run(context.Background(), []request.Messages{request.NewUserMessage("天气如何")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Message.Content != "晴" || result.Iterations != 2 || result.Usage.TotalTokens != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if atomic.LoadInt32(&peak) < 2 {
+		t.Fatalf("tool calls should run concurrently, peak = %d", peak)
+	}
+
+	tools := s.requests[1].Messages[2:]
+	if len(tools) != 5 || tools[0].ToolCallID != "1" || !strings.Contains(tools[0].Content, "北京") {
+		t.Fatalf("unexpected tool messages: %+v", tools)
+	}
+	for i, want := range []string{"北京", "is required", "deadline exceeded", "rejected", "杭州"} {
+		if !strings.Contains(tools[i].Content, want) {
+			t.Errorf("tool message %d = %q, want %q", i, tools[i].Content, want)
+		}
+	}
+	if len(rejected) != 1 || len(s.requests[0].Tools) != 2 {
+		t.Fatalf("rejected = %v, tools = %d", rejected, len(s.requests[0].Tools))
+	}
+}
+
+func Test_MaxIterations(t *testing.T) {
+	loop := response.Message{Role: "assistant", ToolCalls: []response.ToolCall{toolCall("1", "missing", "")}}
+	s := &script{replies: []response.Message{loop, loop, loop}}
+
+	result, err := New(s, WithMaxIterations(2)).Run(context.Background(), []request.Messages{request.NewUserMessage("hi")})
+	if !errors.Is(err, ErrMaxIterations) || result.Iterations != 2 {
+		t.Fatalf("Run = %+v, %v", result, err)
+	}
+	if !strings.Contains(result.Messages[2].Content, "unknown tool") {
+		t.Fatalf("unexpected tool message: %+v", result.Messages[2])
+	}
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/jsonschema"
+	"github.com/jun3372/uniai/request"
+)
+
+// Tool 是一个可以被模型调用的工具
+type Tool struct {
+	Name        string // 工具名称，需在同一个 Runner 中唯一
+	Description string // 工具描述，帮助模型判断何时调用
+	Parameters  any    // 参数的 JSON Schema，通常为 *jsonschema.Schema，也可以是解码后的原始 Schema
+	// Handler 执行工具调用，arguments 为模型生成的 JSON 参数，返回的文本会作为工具消息反馈给模型
+	Handler func(ctx context.Context, arguments string) (string, error)
+}
+
+// NewTool 将 Go 函数注册为工具，参数的 JSON Schema 根据类型 T 自动生成。
+// 模型生成的参数会先按 Schema 校验再解码为 T，校验失败时错误会反馈给模型。
+// fn 返回字符串时原样反馈，返回其他类型时序列化为 JSON 后反馈。
+func NewTool[T any](name, description string, fn func(ctx context.Context, args T) (any, error)) Tool {
+	schema := jsonschema.For[T]()
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  schema,
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			if arguments == "" {
+				arguments = "{}"
+			}
+
+			var raw any
+			if err := sonic.ConfigDefault.UnmarshalFromString(arguments, &raw); err != nil {
+				return "", errors.Wrap(err, "arguments are not valid json")
+			}
+			if err := jsonschema.Validate(schema, raw); err != nil {
+				return "", err
+			}
+
+			var args T
+			if err := sonic.ConfigDefault.UnmarshalFromString(arguments, &args); err != nil {
+				return "", err
+			}
+
+			result, err := fn(ctx, args)
+			if err != nil {
+				return "", err
+			}
+			if s, ok := result.(string); ok {
+				return s, nil
+			}
+			return sonic.ConfigDefault.MarshalToString(result)
+		},
+	}
+}
+
+// definition 返回工具在请求中的定义
+func (t Tool) definition() request.Tool {
+	return request.NewFunctionTool(t.Name, t.Description, t.Parameters)
+}
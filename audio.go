@@ -0,0 +1,57 @@
+package uniai
+
+import (
+	"context"
+	"io"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Audio 接口定义了语音识别与语音合成能力。
+type Audio interface {
+	// Transcribe 将音频转换为文字。
+	Transcribe(ctx context.Context, in request.TranscriptionRequest) (*response.TranscriptionResponse, error)
+	// Speech 将文字合成为语音，音频数据会边接收边写入 w，返回写入的字节数。
+	Speech(ctx context.Context, in request.SpeechRequest, w io.Writer) (int64, error)
+}
+
+// audio 结构体实现了 Audio 接口
+type audio struct {
+	u *uniai
+}
+
+// Audio 方法返回语音识别与语音合成能力
+func (u *uniai) Audio() Audio {
+	return audio{u: u}
+}
+
+// Transcribe 将音频转换为文字，客户端不支持语音识别时返回 errorx.NotSupported。
+// 请求的模型是 client.WithRoute 设置的别名时，请求会被转发给别名对应的服务商与模型。
+func (a audio) Transcribe(ctx context.Context, in request.TranscriptionRequest) (*response.TranscriptionResponse, error) {
+	target, route, ok := a.u.route(in.Model)
+	if ok {
+		in.Model = route.Model
+	}
+	t, ok := target.getClient().(client.AudioTranscriber)
+	if !ok {
+		return nil, errorx.NotSupported
+	}
+	return t.Transcriptions(*target.opts, ctx, in)
+}
+
+// Speech 将文字合成为语音，客户端不支持语音合成时返回 errorx.NotSupported。
+// 请求的模型是 client.WithRoute 设置的别名时，请求会被转发给别名对应的服务商与模型。
+func (a audio) Speech(ctx context.Context, in request.SpeechRequest, w io.Writer) (int64, error) {
+	target, route, ok := a.u.route(in.Model)
+	if ok {
+		in.Model = route.Model
+	}
+	s, ok := target.getClient().(client.SpeechSynthesizer)
+	if !ok {
+		return 0, errorx.NotSupported
+	}
+	return s.Speech(*target.opts, ctx, in, w)
+}
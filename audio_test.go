@@ -0,0 +1,76 @@
+package uniai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_Audio(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/audio/transcriptions":
+			// 音频以流的形式上传，请求体的长度未知
+			if r.ContentLength != -1 {
+				t.Errorf("upload should be streamed, content length = %d", r.ContentLength)
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			if header.Filename != "hello.mp3" || string(data) != "RIFF" || r.FormValue("model") != "whisper-1" || r.FormValue("language") != "zh" {
+				t.Errorf("unexpected upload: %s %q %v", header.Filename, data, r.MultipartForm.Value)
+			}
+			w.Write([]byte(`{"text":"你好"}`))
+		case "/v1/audio/speech":
+			if r.Header.Get("Authorization") != "Bearer sk" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"message":"invalid api key","type":"invalid_request_error"}}`))
+				return
+			}
+			w.Write([]byte("ID3-audio"))
+		}
+	}))
+	defer srv.Close()
+
+	ai := New(client.WithHost(srv.URL), client.WithHeader(http.Header{"Authorization": {"Bearer sk"}}))
+	resp, err := ai.Audio().Transcribe(context.Background(), *request.NewTranscriptionRequest(
+		request.WithTranscriptionModel("whisper-1"),
+		request.WithFile("hello.mp3", strings.NewReader("RIFF")),
+		request.WithLanguage("zh"),
+	))
+	if err != nil || resp.Text != "你好" {
+		t.Fatalf("Transcribe = %+v, %v", resp, err)
+	}
+
+	buf := &bytes.Buffer{}
+	n, err := ai.Audio().Speech(context.Background(), *request.NewSpeechRequest(request.WithSpeechInput("你好"), request.WithVoice("alloy")), buf)
+	if err != nil || n != 9 || buf.String() != "ID3-audio" {
+		t.Fatalf("Speech = %d, %q, %v", n, buf.String(), err)
+	}
+
+	ai = New(client.WithHost(srv.URL))
+	_, err = ai.Audio().Speech(context.Background(), *request.NewSpeechRequest(request.WithSpeechInput("你好")), io.Discard)
+	if !errors.Is(err, errorx.Unauthorized) {
+		t.Fatalf("err = %v, want Unauthorized", err)
+	}
+
+	// 读取音频失败时上传中止并返回错误
+	broken := errors.New("disk error")
+	_, err = ai.Audio().Transcribe(context.Background(), *request.NewTranscriptionRequest(request.WithFile("a.mp3", iotest.ErrReader(broken))))
+	if err == nil || !strings.Contains(err.Error(), broken.Error()) {
+		t.Fatalf("err = %v, want the read error", err)
+	}
+}
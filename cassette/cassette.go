@@ -0,0 +1,170 @@
+// Package cassette 提供录制与回放服务商 HTTP 流量的 http.RoundTripper。
+//
+// 录制模式下请求被转发给真实的服务商，请求与响应（包括 SSE 流的每个分片及其时间偏移）在脱敏后保存到文件；
+// 回放模式下按请求方法、路径与规范化后的请求体匹配录制的交互并返回，不访问网络。
+// 通过 client.WithTransport 接入后，适配器的逻辑无需任何修改：
+//
+//	rec, err := cassette.New("testdata/spark.json", cassette.ModeAuto)
+//	ai := uniai.New(client.WithType(client.Xfyun), client.WithTransport(rec))
+//	defer rec.Stop()
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+)
+
+// Redacted 是脱敏后的凭证值
+const Redacted = "REDACTED"
+
+// encodingBase64 表示内容以 base64 编码保存
+const encodingBase64 = "base64"
+
+// Cassette 是录制文件的内容
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"` // 按完成顺序排列的交互
+}
+
+// Interaction 是一次请求与响应的交换
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 是录制的请求
+type Request struct {
+	Method       string      `json:"method"`                  // 请求方法
+	Path         string      `json:"path"`                    // 请求路径
+	Query        string      `json:"query,omitempty"`         // 脱敏后的查询参数
+	Header       http.Header `json:"header,omitempty"`        // 脱敏后的请求头
+	Body         string      `json:"body,omitempty"`          // 请求体
+	BodyEncoding string      `json:"body_encoding,omitempty"` // 请求体的编码，非 UTF-8 内容为 base64
+}
+
+// Response 是录制的响应，SSE 流保存在 Chunks 中，其余响应保存在 Body 中
+type Response struct {
+	StatusCode   int         `json:"status_code"`             // HTTP 状态码
+	Header       http.Header `json:"header,omitempty"`        // 脱敏后的响应头
+	Body         string      `json:"body,omitempty"`          // 响应体
+	BodyEncoding string      `json:"body_encoding,omitempty"` // 响应体的编码，非 UTF-8 内容为 base64
+	Chunks       []Chunk     `json:"chunks,omitempty"`        // SSE 流的分片
+}
+
+// Chunk 是 SSE 流中一次读取到的数据
+type Chunk struct {
+	Offset   int64  `json:"offset_ms"`          // 相对于收到响应头的时间偏移，单位为毫秒
+	Data     string `json:"data"`               // 分片内容
+	Encoding string `json:"encoding,omitempty"` // 分片内容的编码，读取边界切断多字节字符等非 UTF-8 内容为 base64
+}
+
+// Load 读取录制文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := sonic.ConfigDefault.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save 将录制内容写入文件，目录不存在时会自动创建
+func (c *Cassette) Save(path string) error {
+	data, err := sonic.ConfigStd.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// body 返回解码后的响应体
+func (r Response) body() ([]byte, error) {
+	return decodeBody(r.Body, r.BodyEncoding)
+}
+
+// encodeBody 将内容编码为可保存在 JSON 中的字符串
+func encodeBody(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), encodingBase64
+}
+
+// decodeBody 解码 encodeBody 保存的内容
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == encodingBase64 {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+// isStream 判断响应是否为 SSE 流
+func isStream(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+// redactHeader 复制请求头并替换其中的凭证
+func redactHeader(header http.Header, keys map[string]bool) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	out := header.Clone()
+	for key := range out {
+		if keys[http.CanonicalHeaderKey(key)] {
+			out[key] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// redactQuery 替换查询参数中的凭证
+func redactQuery(query url.Values, keys map[string]bool) string {
+	if len(query) == 0 {
+		return ""
+	}
+	out := url.Values{}
+	for key, values := range query {
+		if keys[strings.ToLower(key)] {
+			values = []string{Redacted}
+		}
+		out[key] = values
+	}
+	return out.Encode()
+}
+
+// normalize 规范化请求体：JSON 请求体按键名排序并去掉忽略的顶层字段，其余内容保持原样
+func normalize(body []byte, ignore map[string]bool) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := sonic.ConfigDefault.Unmarshal(trimmed, &v); err != nil {
+		return body
+	}
+	if m, ok := v.(map[string]any); ok {
+		for key := range ignore {
+			delete(m, key)
+		}
+	}
+	// ConfigStd 会按键名排序输出对象
+	out, err := sonic.ConfigStd.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
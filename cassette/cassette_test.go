@@ -0,0 +1,128 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/cassette"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func ask(ai interface {
+	Completions(context.Context, request.Request) (chan response.Response, error)
+}, stream bool, content string) (string, error) {
+	out, err := ai.Completions(context.Background(), *request.NewRequest(
+		request.WithModel("test-model"),
+		request.WithStream(stream),
+		request.WithMessages([]request.Messages{request.NewUserMessage(content)}),
+	))
+	if err != nil {
+		return "", err
+	}
+	acc := response.NewAccumulator()
+	for item := range out {
+		acc.Add(item)
+	}
+	return acc.Message().Content, nil
+}
+
+func Test_RecordReplay(t *testing.T) {
+	for _, tt := range []struct{ format, typ string }{{uniaitest.FormatOpenAI, client.Tongyi}, {uniaitest.FormatSpark, client.Xfyun}} {
+		t.Run(tt.typ, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.typ+".json")
+			srv := uniaitest.NewServer(t, tt.format, uniaitest.Text("你好", "，世界"), uniaitest.Text("ok"))
+
+			rec, err := cassette.New(path, cassette.ModeAuto)
+			if err != nil || !rec.Recording() {
+				t.Fatalf("New = %v, recording = %v", err, rec.Recording())
+			}
+			ai := uniai.New(client.WithType(tt.typ), client.WithHost(srv.URL), client.AddHeader("Authorization", "Bearer sk-secret"), client.WithTransport(rec))
+			if got, err := ask(ai, true, "hi"); err != nil || got != "你好，世界" {
+				t.Fatalf("record stream = %q, %v", got, err)
+			}
+			if got, err := ask(ai, false, "again"); err != nil || got != "ok" {
+				t.Fatalf("record = %q, %v", got, err)
+			}
+			if err := rec.Stop(); err != nil {
+				t.Fatal(err)
+			}
+			srv.Close()
+
+			data, _ := os.ReadFile(path)
+			if strings.Contains(string(data), "sk-secret") || !strings.Contains(string(data), cassette.Redacted) {
+				t.Fatalf("credentials should be redacted: %s", data)
+			}
+
+			rec, err = cassette.New(path, cassette.ModeReplay)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ai = uniai.New(client.WithType(tt.typ), client.WithHost(srv.URL), client.WithTransport(rec))
+			// 回放时按请求匹配，与录制顺序无关
+			if got, err := ask(ai, false, "again"); err != nil || got != "ok" {
+				t.Fatalf("replay = %q, %v", got, err)
+			}
+			if got, err := ask(ai, true, "hi"); err != nil || got != "你好，世界" {
+				t.Fatalf("replay stream = %q, %v", got, err)
+			}
+			if _, err := ask(ai, false, "unknown"); err == nil {
+				t.Fatal("unmatched request should fail")
+			}
+			_, err = (&http.Client{Transport: rec}).Post(srv.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+			if !errors.Is(err, errorx.NotFound) {
+				t.Fatalf("err = %v, want NotFound", err)
+			}
+		})
+	}
+}
+
+func Test_SplitRune(t *testing.T) {
+	const stream = "data: 你好\n\ndata: [DONE]\n\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(stream))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "split.json")
+	read := func(mode cassette.Mode) string {
+		t.Helper()
+		rec, err := cassette.New(path, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := (&http.Client{Transport: rec}).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 逐字节读取，使读取边界切断多字节字符
+		data, err := io.ReadAll(iotest.OneByteReader(resp.Body))
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rec.Stop(); err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if got := read(cassette.ModeRecord); got != stream {
+		t.Fatalf("record = %q", got)
+	}
+	if got := read(cassette.ModeReplay); got != stream {
+		t.Fatalf("replay = %q", got)
+	}
+}
@@ -0,0 +1,357 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+)
+
+// Mode 表示 Recorder 的工作模式
+type Mode int
+
+// 以下为 Recorder 支持的工作模式
+const (
+	ModeReplay Mode = iota // 仅回放录制文件，未匹配的请求返回错误，适合在 CI 中使用
+	ModeRecord             // 转发请求并录制，Stop 时覆盖录制文件
+	ModeAuto               // 录制文件存在时回放，否则录制
+)
+
+// Options 结构体定义了 Recorder 的选项
+type Options struct {
+	Transport     http.RoundTripper // 录制时转发请求使用的 http.RoundTripper，默认为 http.DefaultTransport
+	Realtime      bool              // 回放 SSE 流时是否按录制的时间偏移输出分片，默认立即输出
+	RedactHeaders []string          // 需要脱敏的请求头与响应头，会追加到默认列表中
+	RedactQuery   []string          // 需要脱敏的查询参数，会追加到默认列表中
+	IgnoreFields  []string          // 匹配请求时忽略的 JSON 请求体顶层字段，如随机生成的 request_id
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// WithTransport 设置录制时转发请求使用的 http.RoundTripper
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *Options) { o.Transport = rt }
+}
+
+// WithRealtime 设置回放 SSE 流时按录制的时间偏移输出分片，用于测试超时与取消等与时间相关的逻辑
+func WithRealtime() Option {
+	return func(o *Options) { o.Realtime = true }
+}
+
+// WithRedactHeaders 追加需要脱敏的请求头与响应头
+func WithRedactHeaders(keys ...string) Option {
+	return func(o *Options) { o.RedactHeaders = append(o.RedactHeaders, keys...) }
+}
+
+// WithRedactQuery 追加需要脱敏的查询参数
+func WithRedactQuery(keys ...string) Option {
+	return func(o *Options) { o.RedactQuery = append(o.RedactQuery, keys...) }
+}
+
+// WithIgnoreFields 设置匹配请求时忽略的 JSON 请求体顶层字段
+func WithIgnoreFields(fields ...string) Option {
+	return func(o *Options) { o.IgnoreFields = append(o.IgnoreFields, fields...) }
+}
+
+// 默认脱敏的请求头与查询参数，覆盖各服务商的鉴权方式
+var (
+	defaultRedactHeaders = []string{"Authorization", "Api-Key", "X-Api-Key", "Cookie", "Set-Cookie", "X-Dashscope-Apikey"}
+	defaultRedactQuery   = []string{"access_token", "api_key", "apikey", "key", "client_id", "client_secret"}
+)
+
+// Recorder 是录制与回放 HTTP 流量的 http.RoundTripper，可并发使用
+type Recorder struct {
+	path      string
+	recording bool
+	opts      Options
+	headers   map[string]bool
+	query     map[string]bool
+	ignore    map[string]bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New 创建一个 Recorder，path 为录制文件的路径
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{path: path, opts: Options{Transport: http.DefaultTransport}}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+
+	r.headers = make(map[string]bool)
+	for _, key := range append(defaultRedactHeaders, r.opts.RedactHeaders...) {
+		r.headers[http.CanonicalHeaderKey(key)] = true
+	}
+	r.query = make(map[string]bool)
+	for _, key := range append(defaultRedactQuery, r.opts.RedactQuery...) {
+		r.query[strings.ToLower(key)] = true
+	}
+	r.ignore = make(map[string]bool)
+	for _, field := range r.opts.IgnoreFields {
+		r.ignore[field] = true
+	}
+
+	switch mode {
+	case ModeRecord:
+		r.recording = true
+	case ModeAuto:
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.recording = true
+		}
+	case ModeReplay:
+	default:
+		return nil, errors.Wrapf(errorx.InvalidInput, "unknown cassette mode %d", mode)
+	}
+
+	if r.recording {
+		r.cassette = &Cassette{}
+		return r, nil
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load cassette")
+	}
+	r.cassette = c
+	r.used = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Recording 返回 Recorder 是否处于录制状态
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Stop 结束录制并写入录制文件，回放状态下不做任何操作
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	if r.recording {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// record 转发请求并在响应体读取完毕后保存交互
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.opts.Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	in := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  redactQuery(req.URL.Query(), r.query),
+		Header: redactHeader(req.Header, r.headers),
+	}
+	in.Body, in.BodyEncoding = encodeBody(body)
+
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		start:      time.Now(),
+		stream:     isStream(resp.Header),
+		done: func(rec Response) {
+			rec.StatusCode = resp.StatusCode
+			rec.Header = redactHeader(resp.Header, r.headers)
+			r.mu.Lock()
+			r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: in, Response: rec})
+			r.mu.Unlock()
+		},
+	}
+	return resp, nil
+}
+
+// replay 返回第一个尚未使用且匹配请求的交互，相同的请求按录制顺序依次回放
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	want := normalize(body, r.ignore)
+
+	r.mu.Lock()
+	var found *Interaction
+	for i := range r.cassette.Interactions {
+		item := &r.cassette.Interactions[i]
+		if r.used[i] || item.Request.Method != req.Method || item.Request.Path != req.URL.Path {
+			continue
+		}
+		recorded, err := decodeBody(item.Request.Body, item.Request.BodyEncoding)
+		if err != nil || !bytes.Equal(normalize(recorded, r.ignore), want) {
+			continue
+		}
+		r.used[i] = true
+		found = item
+		break
+	}
+	r.mu.Unlock()
+
+	if found == nil {
+		return nil, errors.Wrapf(errorx.NotFound, "cassette: no interaction for %s %s", req.Method, req.URL.Path)
+	}
+
+	resp := &http.Response{
+		Status:        http.StatusText(found.Response.StatusCode),
+		StatusCode:    found.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        found.Response.Header.Clone(),
+		ContentLength: -1,
+		Request:       req,
+	}
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+
+	if isStream(found.Response.Header) {
+		resp.Body = &replayBody{chunks: found.Response.Chunks, realtime: r.opts.Realtime, start: time.Now(), ctx: req.Context()}
+		return resp, nil
+	}
+
+	data, err := found.Response.body()
+	if err != nil {
+		return nil, errors.Wrap(err, "decode cassette body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
+
+// recordingBody 在读取响应体的同时录制内容，读取完毕或关闭时保存交互
+type recordingBody struct {
+	io.ReadCloser
+	start  time.Time
+	stream bool
+	buf    bytes.Buffer
+	chunks []Chunk
+	done   func(Response)
+	mu     sync.Mutex
+	saved  bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	if n > 0 && !b.saved {
+		if b.stream {
+			chunk := Chunk{Offset: time.Since(b.start).Milliseconds()}
+			chunk.Data, chunk.Encoding = encodeBody(p[:n])
+			b.chunks = append(b.chunks, chunk)
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	b.mu.Unlock()
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+// finish 保存录制的响应，只执行一次；响应体未读取完毕就被关闭时保存已读取的部分
+func (b *recordingBody) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.saved {
+		return
+	}
+	b.saved = true
+
+	var rec Response
+	if b.stream {
+		rec.Chunks = b.chunks
+	} else {
+		rec.Body, rec.BodyEncoding = encodeBody(b.buf.Bytes())
+	}
+	b.done(rec)
+}
+
+// replayBody 逐个输出录制的 SSE 分片
+type replayBody struct {
+	chunks   []Chunk
+	realtime bool
+	start    time.Time
+	ctx      context.Context
+	pending  []byte
+	mu       sync.Mutex
+	closed   bool
+}
+
+func (b *replayBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, errors.New("read on closed response body")
+	}
+
+	if len(b.pending) == 0 {
+		if len(b.chunks) == 0 {
+			return 0, io.EOF
+		}
+		chunk := b.chunks[0]
+		b.chunks = b.chunks[1:]
+		if b.realtime {
+			if wait := time.Until(b.start.Add(time.Duration(chunk.Offset) * time.Millisecond)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-b.ctx.Done():
+					timer.Stop()
+					return 0, b.ctx.Err()
+				}
+			}
+		}
+		data, err := decodeBody(chunk.Data, chunk.Encoding)
+		if err != nil {
+			return 0, errors.Wrap(err, "decode cassette chunk")
+		}
+		b.pending = data
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func (b *replayBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
@@ -0,0 +1,165 @@
+// Package catalog 提供模型元数据目录，记录各模型的上下文长度、最大输出长度、能力与价格。
+// 目录可以在发送请求前校验参数，也可以供路由按能力挑选模型。
+// 内置目录 Default 收录了常见模型，调用方可以通过 Register 覆盖或补充。
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Capabilities 结构体定义了模型支持的能力
+type Capabilities struct {
+	Tools      bool // 是否支持工具调用
+	Vision     bool // 是否支持图像输入
+	JSONMode   bool // 是否支持 json_object 输出格式
+	JSONSchema bool // 是否支持 json_schema 输出格式
+	Reasoning  bool // 是否为推理模型，会输出思考过程
+	Embedding  bool // 是否为向量模型
+}
+
+// Pricing 结构体定义了模型的价格，单位为每百万 Token
+type Pricing struct {
+	Input    float64 // 输入的价格
+	Output   float64 // 输出的价格
+	Currency string  // 币种，如 USD、CNY
+}
+
+// Model 结构体定义了一个模型的元数据
+type Model struct {
+	Name            string       // 模型名称，如 gpt-4o
+	Provider        string       // 服务商类型，与 client.Options.Type 一致
+	ContextWindow   int          // 上下文长度，单位为 Token
+	MaxOutputTokens int          // 最大输出长度，单位为 Token
+	Capabilities    Capabilities // 模型支持的能力
+	Pricing         Pricing      // 模型的价格
+}
+
+// Cost 根据用量统计计算本次请求的费用，币种与 Pricing.Currency 一致
+func (m Model) Cost(usage *response.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*m.Pricing.Input + float64(usage.CompletionTokens)*m.Pricing.Output) / 1e6
+}
+
+// Catalog 是一个并发安全的模型元数据目录
+type Catalog struct {
+	mu     sync.RWMutex
+	models map[string]Model
+}
+
+// New 创建一个包含指定模型的目录
+func New(models ...Model) *Catalog {
+	c := &Catalog{models: make(map[string]Model, len(models))}
+	c.Register(models...)
+	return c
+}
+
+// Default 是内置的模型目录
+var Default = New(builtin...)
+
+// Register 注册模型元数据，同名模型会被覆盖
+func (c *Catalog) Register(models ...Model) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range models {
+		c.models[m.Name] = m
+	}
+}
+
+// Lookup 查找模型的元数据。
+// 未找到同名模型时，会匹配最长的名称前缀，使带日期后缀的版本（如 gpt-4o-2024-08-06）沿用基础模型的元数据。
+func (c *Catalog) Lookup(name string) (Model, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if m, ok := c.models[name]; ok {
+		return m, true
+	}
+
+	var (
+		best  Model
+		found bool
+	)
+	for key, m := range c.models {
+		if strings.HasPrefix(name, key+"-") && len(key) > len(best.Name) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+// Models 返回满足所有过滤条件的模型，结果按名称排序
+func (c *Catalog) Models(filters ...func(Model) bool) []Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]Model, 0, len(c.models))
+next:
+	for _, m := range c.models {
+		for _, filter := range filters {
+			if !filter(m) {
+				continue next
+			}
+		}
+		out = append(out, m)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ByProvider 返回按服务商过滤的条件
+func ByProvider(provider string) func(Model) bool {
+	return func(m Model) bool { return m.Provider == provider }
+}
+
+// ByCapability 返回按能力过滤的条件，如 catalog.ByCapability(func(c catalog.Capabilities) bool { return c.Tools })
+func ByCapability(has func(Capabilities) bool) func(Model) bool {
+	return func(m Model) bool { return has(m.Capabilities) }
+}
+
+// Validate 根据模型的元数据校验请求，目录中不存在的模型不做校验。
+// 请求使用了模型不支持的能力（工具调用、json_object 与 json_schema 输出格式）或 max_tokens 超出上限时，
+// 返回包装了 errorx.InvalidInput 的错误。消息目前只有文本内容，因此不涉及图像输入能力的校验。
+func (c *Catalog) Validate(in request.Request) error {
+	m, ok := c.Lookup(in.Model)
+	if !ok {
+		return nil
+	}
+
+	if m.Capabilities.Embedding {
+		return errors.Wrapf(errorx.InvalidInput, "model %s does not support chat completions", in.Model)
+	}
+	if len(in.Tools) > 0 && !m.Capabilities.Tools {
+		return errors.Wrapf(errorx.InvalidInput, "model %s does not support tools", in.Model)
+	}
+	if in.ResponseFormat != nil {
+		switch in.ResponseFormat.Type {
+		case request.ResponseFormatJSONObject:
+			if !m.Capabilities.JSONMode {
+				return errors.Wrapf(errorx.InvalidInput, "model %s does not support response format %s", in.Model, in.ResponseFormat.Type)
+			}
+		case request.ResponseFormatJSONSchema:
+			if !m.Capabilities.JSONSchema {
+				return errors.Wrapf(errorx.InvalidInput, "model %s does not support response format %s", in.Model, in.ResponseFormat.Type)
+			}
+		}
+	}
+	if m.MaxOutputTokens > 0 {
+		for _, limit := range []*int{in.MaxTokens, in.MaxCompletionTokens} {
+			if limit != nil && *limit > m.MaxOutputTokens {
+				return errors.Wrapf(errorx.InvalidInput, "model %s supports at most %d output tokens, got %d", in.Model, m.MaxOutputTokens, *limit)
+			}
+		}
+	}
+	return nil
+}
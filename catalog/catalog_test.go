@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+func Test_Lookup(t *testing.T) {
+	m, ok := Default.Lookup("gpt-4o-2024-08-06")
+	if !ok || m.Name != "gpt-4o" {
+		t.Fatalf("Lookup = %+v, %v, want gpt-4o", m, ok)
+	}
+
+	m, ok = Default.Lookup("gpt-4o-mini-2024-07-18")
+	if !ok || m.Name != "gpt-4o-mini" {
+		t.Fatalf("Lookup = %+v, %v, want gpt-4o-mini", m, ok)
+	}
+
+	if _, ok := Default.Lookup("unknown"); ok {
+		t.Fatal("unknown model should not be found")
+	}
+
+	c := New(Model{Name: "gpt-4o", ContextWindow: 1})
+	if m, _ := c.Lookup("gpt-4o"); m.ContextWindow != 1 {
+		t.Fatalf("override not applied: %+v", m)
+	}
+
+	if cost := Default.Models(ByProvider(client.OpenAI))[0].Cost(&response.Usage{PromptTokens: 1e6}); cost <= 0 {
+		t.Fatalf("cost = %v", cost)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	tool := request.NewFunctionTool("get_weather", "", nil)
+
+	tests := []struct {
+		name string
+		in   request.Request
+		ok   bool
+	}{
+		{"unknown model", request.Request{Model: "my-model", Tools: []request.Tool{tool}}, true},
+		{"tools supported", request.Request{Model: "gpt-4o", Tools: []request.Tool{tool}}, true},
+		{"tools unsupported", request.Request{Model: "hunyuan-lite", Tools: []request.Tool{tool}}, false},
+		{"max tokens", request.Request{Model: "gpt-3.5-turbo", MaxTokens: request.Ptr(8192)}, false},
+		{"embedding model", request.Request{Model: "text-embedding-3-small"}, false},
+		{"json object supported", request.Request{Model: "gpt-3.5-turbo", ResponseFormat: &request.ResponseFormat{Type: request.ResponseFormatJSONObject}}, true},
+		{"json object unsupported", request.Request{Model: "hunyuan-lite", ResponseFormat: &request.ResponseFormat{Type: request.ResponseFormatJSONObject}}, false},
+		{"json schema supported", request.Request{Model: "gpt-4o", ResponseFormat: &request.ResponseFormat{Type: request.ResponseFormatJSONSchema}}, true},
+		{"json schema unsupported", request.Request{Model: "gpt-3.5-turbo", ResponseFormat: &request.ResponseFormat{Type: request.ResponseFormatJSONSchema}}, false},
+		{"text format", request.Request{Model: "hunyuan-lite", ResponseFormat: &request.ResponseFormat{Type: request.ResponseFormatText}}, true},
+	}
+
+	for _, tt := range tests {
+		err := Default.Validate(tt.in)
+		if tt.ok != (err == nil) || (err != nil && !errors.Is(err, errorx.InvalidInput)) {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+	}
+}
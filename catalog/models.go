@@ -0,0 +1,56 @@
+package catalog
+
+import "github.com/jun3372/uniai/client"
+
+// 常用的能力组合
+var (
+	chat      = Capabilities{Tools: true, JSONMode: true}
+	chatFull  = Capabilities{Tools: true, Vision: true, JSONMode: true, JSONSchema: true}
+	reasoning = Capabilities{Tools: true, JSONMode: true, JSONSchema: true, Reasoning: true}
+	embedding = Capabilities{Embedding: true}
+)
+
+// builtin 是内置目录收录的模型，价格取自各服务商的公开价格，仅供估算参考。
+var builtin = []Model{
+	// OpenAI
+	{Name: "gpt-4o", Provider: client.OpenAI, ContextWindow: 128000, MaxOutputTokens: 16384, Capabilities: chatFull, Pricing: Pricing{Input: 2.5, Output: 10, Currency: "USD"}},
+	{Name: "gpt-4o-mini", Provider: client.OpenAI, ContextWindow: 128000, MaxOutputTokens: 16384, Capabilities: chatFull, Pricing: Pricing{Input: 0.15, Output: 0.6, Currency: "USD"}},
+	{Name: "gpt-4.1", Provider: client.OpenAI, ContextWindow: 1047576, MaxOutputTokens: 32768, Capabilities: chatFull, Pricing: Pricing{Input: 2, Output: 8, Currency: "USD"}},
+	{Name: "gpt-4.1-mini", Provider: client.OpenAI, ContextWindow: 1047576, MaxOutputTokens: 32768, Capabilities: chatFull, Pricing: Pricing{Input: 0.4, Output: 1.6, Currency: "USD"}},
+	{Name: "gpt-3.5-turbo", Provider: client.OpenAI, ContextWindow: 16385, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Input: 0.5, Output: 1.5, Currency: "USD"}},
+	{Name: "o1", Provider: client.OpenAI, ContextWindow: 200000, MaxOutputTokens: 100000, Capabilities: Capabilities{Tools: true, Vision: true, JSONMode: true, JSONSchema: true, Reasoning: true}, Pricing: Pricing{Input: 15, Output: 60, Currency: "USD"}},
+	{Name: "o3-mini", Provider: client.OpenAI, ContextWindow: 200000, MaxOutputTokens: 100000, Capabilities: reasoning, Pricing: Pricing{Input: 1.1, Output: 4.4, Currency: "USD"}},
+	{Name: "text-embedding-3-small", Provider: client.OpenAI, ContextWindow: 8191, Capabilities: embedding, Pricing: Pricing{Input: 0.02, Currency: "USD"}},
+	{Name: "text-embedding-3-large", Provider: client.OpenAI, ContextWindow: 8191, Capabilities: embedding, Pricing: Pricing{Input: 0.13, Currency: "USD"}},
+
+	// 通义千问
+	{Name: "qwen-max", Provider: client.Tongyi, ContextWindow: 32768, MaxOutputTokens: 8192, Capabilities: chat, Pricing: Pricing{Input: 2.4, Output: 9.6, Currency: "CNY"}},
+	{Name: "qwen-plus", Provider: client.Tongyi, ContextWindow: 131072, MaxOutputTokens: 8192, Capabilities: chat, Pricing: Pricing{Input: 0.8, Output: 2, Currency: "CNY"}},
+	{Name: "qwen-turbo", Provider: client.Tongyi, ContextWindow: 1000000, MaxOutputTokens: 8192, Capabilities: chat, Pricing: Pricing{Input: 0.3, Output: 0.6, Currency: "CNY"}},
+	{Name: "qwen-vl-max", Provider: client.Tongyi, ContextWindow: 131072, MaxOutputTokens: 8192, Capabilities: Capabilities{Vision: true, JSONMode: true}, Pricing: Pricing{Input: 3, Output: 9, Currency: "CNY"}},
+	{Name: "qwq-plus", Provider: client.Tongyi, ContextWindow: 131072, MaxOutputTokens: 8192, Capabilities: Capabilities{Tools: true, Reasoning: true}, Pricing: Pricing{Input: 1.6, Output: 4, Currency: "CNY"}},
+	{Name: "text-embedding-v3", Provider: client.Tongyi, ContextWindow: 8192, Capabilities: embedding, Pricing: Pricing{Input: 0.5, Currency: "CNY"}},
+
+	// 智谱
+	{Name: "glm-4-plus", Provider: client.Zhipu, ContextWindow: 128000, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Input: 5, Output: 5, Currency: "CNY"}},
+	{Name: "glm-4-air", Provider: client.Zhipu, ContextWindow: 128000, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Input: 0.5, Output: 0.5, Currency: "CNY"}},
+	{Name: "glm-4-flash", Provider: client.Zhipu, ContextWindow: 128000, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Currency: "CNY"}},
+	{Name: "glm-4v-plus", Provider: client.Zhipu, ContextWindow: 8192, MaxOutputTokens: 1024, Capabilities: Capabilities{Vision: true}, Pricing: Pricing{Input: 4, Output: 4, Currency: "CNY"}},
+	{Name: "embedding-3", Provider: client.Zhipu, ContextWindow: 8192, Capabilities: embedding, Pricing: Pricing{Input: 0.5, Currency: "CNY"}},
+
+	// 腾讯混元
+	{Name: "hunyuan-turbo", Provider: client.Hunyuan, ContextWindow: 32768, MaxOutputTokens: 4096, Capabilities: Capabilities{Tools: true}, Pricing: Pricing{Input: 15, Output: 50, Currency: "CNY"}},
+	{Name: "hunyuan-pro", Provider: client.Hunyuan, ContextWindow: 32768, MaxOutputTokens: 4096, Capabilities: Capabilities{Tools: true}, Pricing: Pricing{Input: 30, Output: 100, Currency: "CNY"}},
+	{Name: "hunyuan-lite", Provider: client.Hunyuan, ContextWindow: 256000, MaxOutputTokens: 6144, Capabilities: Capabilities{}, Pricing: Pricing{Currency: "CNY"}},
+	{Name: "hunyuan-t1-latest", Provider: client.Hunyuan, ContextWindow: 32768, MaxOutputTokens: 32768, Capabilities: Capabilities{Reasoning: true}, Pricing: Pricing{Input: 1, Output: 4, Currency: "CNY"}},
+
+	// 火山方舟（豆包）
+	{Name: "doubao-pro-32k", Provider: client.Ark, ContextWindow: 32768, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Input: 0.8, Output: 2, Currency: "CNY"}},
+	{Name: "doubao-lite-32k", Provider: client.Ark, ContextWindow: 32768, MaxOutputTokens: 4096, Capabilities: chat, Pricing: Pricing{Input: 0.3, Output: 0.6, Currency: "CNY"}},
+	{Name: "doubao-1.5-thinking-pro", Provider: client.Ark, ContextWindow: 131072, MaxOutputTokens: 16384, Capabilities: reasoning, Pricing: Pricing{Input: 4, Output: 16, Currency: "CNY"}},
+
+	// 百度千帆
+	{Name: "ernie-4.0-8k", Provider: client.Baidubce, ContextWindow: 8192, MaxOutputTokens: 2048, Capabilities: Capabilities{Tools: true}, Pricing: Pricing{Input: 30, Output: 90, Currency: "CNY"}},
+	{Name: "ernie-speed-128k", Provider: client.Baidubce, ContextWindow: 131072, MaxOutputTokens: 4096, Capabilities: Capabilities{}, Pricing: Pricing{Currency: "CNY"}},
+	{Name: "embedding-v1", Provider: client.Baidubce, ContextWindow: 384, Capabilities: embedding, Pricing: Pricing{Input: 0.5, Currency: "CNY"}},
+}
@@ -1,7 +1,29 @@
 package client
 
+import "strings"
+
 const (
-	OpenAI = "openai"
-	Tongyi = "tongyi"
-	Xfyun  = "xfyun"
+	OpenAI   = "openai"
+	Tongyi   = "tongyi"
+	Xfyun    = "xfyun"
+	Zhipu    = "zhipu"
+	Hunyuan  = "hunyuan"
+	Ark      = "ark"
+	Baidubce = "baidubce"
 )
+
+// defaultHosts 是各服务商公开接口的默认地址
+var defaultHosts = map[string]string{
+	OpenAI:   "https://api.openai.com",
+	Tongyi:   "https://dashscope.aliyuncs.com",
+	Xfyun:    "https://spark-api-open.xf-yun.com",
+	Zhipu:    "https://open.bigmodel.cn",
+	Hunyuan:  "https://hunyuan.tencentcloudapi.com",
+	Ark:      "https://ark.cn-beijing.volces.com",
+	Baidubce: "https://aip.baidubce.com",
+}
+
+// DefaultHost 返回服务商公开接口的默认地址，未知的服务商返回空字符串。
+func DefaultHost(typ string) string {
+	return defaultHosts[strings.ToLower(typ)]
+}
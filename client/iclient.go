@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"io"
 
 	"github.com/jun3372/uniai/request"
 	"github.com/jun3372/uniai/response"
@@ -23,4 +24,52 @@ type IClient interface {
 	//	chan response.Response - 一个channel，用于接收补全响应的结果。
 	//	error - 如果在请求过程中出现错误，将返回错误信息。
 	Completions(opt Options, ctx context.Context, in request.Request) (chan response.Response, error)
+
+	// Embeddings 方法用于获取文本的向量。
+	// 调用方应保证 in.Input 的条数不超过服务商的单次上限，分批请求由上层完成。
+	// 不支持向量化的客户端返回 errorx.NotSupported。
+	Embeddings(opt Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error)
+}
+
+// EmbeddingBatchSizer 接口由对单次向量化请求的文本条数有上限的客户端实现。
+type EmbeddingBatchSizer interface {
+	// EmbeddingBatchSize 返回指定模型单次请求允许的最大文本条数，0 表示不限制。
+	EmbeddingBatchSize(model string) int
+}
+
+// ResponseFormatSupporter 接口由原生支持 response_format 参数的客户端实现。
+// 未实现该接口或不支持指定格式的客户端，会以提示词的方式模拟输出格式。
+type ResponseFormatSupporter interface {
+	// SupportsResponseFormat 返回客户端是否原生支持指定的输出格式，如 json_object、json_schema。
+	SupportsResponseFormat(formatType string) bool
+}
+
+// ImageGenerator 接口由支持图像生成的客户端实现。
+type ImageGenerator interface {
+	// Images 方法用于根据描述生成图像，异步接口的轮询由客户端内部完成。
+	Images(opt Options, ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error)
+}
+
+// AudioTranscriber 接口由支持语音转文字的客户端实现。
+type AudioTranscriber interface {
+	// Transcriptions 方法用于将音频转换为文字。
+	Transcriptions(opt Options, ctx context.Context, in request.TranscriptionRequest) (*response.TranscriptionResponse, error)
+}
+
+// SpeechSynthesizer 接口由支持文字转语音的客户端实现。
+type SpeechSynthesizer interface {
+	// Speech 方法用于将文字合成为语音，音频数据会边接收边写入 w，返回写入的字节数。
+	Speech(opt Options, ctx context.Context, in request.SpeechRequest, w io.Writer) (int64, error)
+}
+
+// ModelLister 接口由能够查询可用模型列表的客户端实现。
+type ModelLister interface {
+	// ListModels 方法用于获取服务提供的模型列表。
+	ListModels(opt Options, ctx context.Context) (*response.ModelList, error)
+}
+
+// RequestValidator 接口用于在发送请求前校验请求参数，如 catalog.Catalog。
+type RequestValidator interface {
+	// Validate 校验请求参数，返回的错误会直接返回给调用方，请求不会被发送。
+	Validate(in request.Request) error
 }
@@ -1,6 +1,13 @@
 package client
 
-import "net/http"
+import (
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jun3372/uniai/request"
+)
 
 // Options 结构体用于配置选项参数
 type Options struct {
@@ -10,6 +17,35 @@ type Options struct {
 	Host string
 	// Header 字段表示选项的HTTP头部信息
 	Header http.Header
+	// SecretID 字段表示需要请求签名的服务商（如腾讯云）的密钥标识
+	SecretID string
+	// SecretKey 字段表示需要请求签名的服务商（如腾讯云）的密钥
+	SecretKey string
+	// Region 字段表示服务所在的地域，部分服务商需要在请求中携带
+	Region string
+	// ModelMapping 字段将友好的模型名称映射为服务商的模型标识，如火山方舟的推理接入点 ID
+	ModelMapping map[string]string
+	// Validator 字段用于在发送请求前校验请求参数，为 nil 时不做校验
+	Validator RequestValidator
+	// Client 字段用于直接指定客户端实现，设置后 Type 字段将被忽略，常用于注入测试替身
+	Client IClient
+	// HTTPClient 字段表示发送请求使用的 HTTP 客户端，为 nil 时使用 http.DefaultClient
+	HTTPClient *http.Client
+	// StreamIdleTimeout 字段表示流式响应中两个分片之间的最长等待时间，超时后中止响应，0 表示不限制
+	StreamIdleTimeout time.Duration
+	// SkipMalformedChunks 字段表示是否跳过流式响应中无法解析的分片，默认遇到时中止响应并返回 errorx.ChunkError
+	SkipMalformedChunks bool
+	// Routes 字段以模型别名为键，请求中的模型名称等于别名时，请求会在调用时被转发给别名对应的服务商与模型
+	Routes map[string]Route
+
+	hostSet bool // 是否通过 WithHost 显式设置了 Host
+}
+
+// Route 结构体定义了模型别名对应的目标
+type Route struct {
+	Options []Option         // 目标服务商的客户端选项，如 WithType、WithHost 与 AddHeader，为空时使用当前客户端
+	Model   string           // 目标模型名称
+	Params  []request.Option // 补全请求的默认参数，只会填充请求中未设置的参数
 }
 
 // Option 是一个函数类型，用于修改Options结构体
@@ -18,6 +54,7 @@ type Option func(*Options)
 // NewOptions 创建一个新的 Options 结构体实例，并允许通过可变参数传入 Option 配置。
 // Option 是一个函数类型，它接受一个指向 Options 的指针作为参数，用于配置 Options。
 // 通过遍历传入的 Option 参数，并对每个 Option 调用，将配置应用到新创建的 Options 实例上。
+// 未通过 WithHost 设置地址时，使用 Type 对应服务商公开接口的默认地址，见 DefaultHost。
 func NewOptions(opts ...Option) *Options {
 	o := &Options{ // 创建一个 Options 结构体指针
 		Host:   "https://api.openai.com", // 设置默认的 Host
@@ -42,15 +79,21 @@ func AddHeader(key, value string) Option {
 	}
 }
 
+// WithHost 设置接口地址，设置后 WithType 不再使用服务商的默认地址。
 func WithHost(host string) Option {
 	return func(o *Options) {
 		o.Host = host
+		o.hostSet = true
 	}
 }
 
+// WithType 设置服务商类型，未通过 WithHost 设置地址时同时使用该服务商的默认地址。
 func WithType(t string) Option {
 	return func(o *Options) {
 		o.Type = t
+		if h := DefaultHost(t); h != "" && !o.hostSet {
+			o.Host = h
+		}
 	}
 }
 
@@ -60,3 +103,117 @@ func WithType(t string) Option {
 func WithHeader(header http.Header) Option {
 	return func(o *Options) { o.Header = header }
 }
+
+// WithSecret 设置用于请求签名的密钥对，如腾讯云的 SecretId 与 SecretKey。
+func WithSecret(id, key string) Option {
+	return func(o *Options) {
+		o.SecretID = id
+		o.SecretKey = key
+	}
+}
+
+// WithRegion 设置服务所在的地域。
+func WithRegion(region string) Option {
+	return func(o *Options) { o.Region = region }
+}
+
+// WithModelMapping 设置模型名称映射，请求中的模型名称命中映射时会被替换为对应的模型标识。
+// 例如火山方舟需要使用推理接入点 ID（ep-xxxx）作为模型名称。
+func WithModelMapping(mapping map[string]string) Option {
+	return func(o *Options) {
+		if o.ModelMapping == nil {
+			o.ModelMapping = make(map[string]string, len(mapping))
+		}
+		for k, v := range mapping {
+			o.ModelMapping[k] = v
+		}
+	}
+}
+
+// WithValidator 设置请求参数的校验器，如 catalog.Default，校验失败的请求不会被发送。
+func WithValidator(v RequestValidator) Option {
+	return func(o *Options) { o.Validator = v }
+}
+
+// WithClient 直接指定客户端实现，如 uniaitest.NewClient 返回的测试替身。
+func WithClient(c IClient) Option {
+	return func(o *Options) { o.Client = c }
+}
+
+// WithHTTPClient 设置发送请求使用的 HTTP 客户端，可用于配置超时与代理。
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *Options) { o.HTTPClient = c }
+}
+
+// WithTransport 设置发送请求使用的 http.RoundTripper，如 cassette.Recorder，适配器的逻辑不受影响。
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *Options) { o.HTTPClient = &http.Client{Transport: rt} }
+}
+
+// WithStreamIdleTimeout 设置流式响应中两个分片之间的最长等待时间，服务商停止输出超过该时间后响应以 errorx.StreamTimeout 结束。
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(o *Options) { o.StreamIdleTimeout = d }
+}
+
+// WithSkipMalformedChunks 设置跳过流式响应中无法解析的分片，而不是中止响应。
+func WithSkipMalformedChunks() Option {
+	return func(o *Options) { o.SkipMalformedChunks = true }
+}
+
+// WithRoute 设置模型别名，请求的模型为 alias 时使用 route 指定的服务商、模型与默认参数。
+// 例如将 fast、smart 等逻辑名称指向不同服务商的模型，切换服务商时调用方无需修改代码。
+func WithRoute(alias string, route Route) Option {
+	return func(o *Options) {
+		if o.Routes == nil {
+			o.Routes = make(map[string]Route)
+		}
+		o.Routes[alias] = route
+	}
+}
+
+// WithRoutes 设置多个模型别名，与已设置的别名合并
+func WithRoutes(routes map[string]Route) Option {
+	return func(o *Options) {
+		for alias, route := range routes {
+			WithRoute(alias, route)(o)
+		}
+	}
+}
+
+// HTTP 返回发送请求使用的 HTTP 客户端
+func (o Options) HTTP() *http.Client {
+	if o.HTTPClient != nil {
+		return o.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Derive 以 o 为基础应用 opts 创建新的选项，用于模型别名的目标服务商，不会修改 o。
+// 新的选项继承 o 的 HTTP 客户端、校验器与流式响应的设置，opts 中设置的请求头会替换 o 中的同名请求头。
+// opts 修改了服务商类型时不继承 o 的服务商相关设置（地址、请求头、密钥、地域、模型映射与客户端实现），
+// 以免凭证被发送给其他服务商，此时未设置地址则使用新服务商的默认地址。
+func (o Options) Derive(opts ...Option) *Options {
+	probe := &Options{Header: make(http.Header)}
+	for _, opt := range opts {
+		opt(probe)
+	}
+
+	var d *Options
+	if probe.Type != "" && !strings.EqualFold(probe.Type, o.Type) {
+		d = NewOptions()
+		d.HTTPClient, d.Validator = o.HTTPClient, o.Validator
+		d.StreamIdleTimeout, d.SkipMalformedChunks = o.StreamIdleTimeout, o.SkipMalformedChunks
+	} else {
+		d = &o
+		d.Header = o.Header.Clone()
+		for key := range probe.Header {
+			d.Header.Del(key)
+		}
+		d.ModelMapping = maps.Clone(o.ModelMapping)
+	}
+	d.Routes = nil
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_NewOptionsHost(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, "https://api.openai.com"},
+		{"provider default", []Option{WithType(Zhipu)}, "https://open.bigmodel.cn"},
+		{"case insensitive", []Option{WithType("Hunyuan")}, "https://hunyuan.tencentcloudapi.com"},
+		{"unknown type", []Option{WithType("custom")}, "https://api.openai.com"},
+		{"explicit host", []Option{WithHost("http://localhost:8080"), WithType(Ark)}, "http://localhost:8080"},
+		{"host after type", []Option{WithType(Ark), WithHost("http://localhost:8080")}, "http://localhost:8080"},
+	}
+	for _, c := range cases {
+		if got := NewOptions(c.opts...).Host; got != c.want {
+			t.Errorf("%s: Host = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+type acceptAll struct{}
+
+func (acceptAll) Validate(request.Request) error { return nil }
+
+func Test_Derive(t *testing.T) {
+	hc := &http.Client{}
+	base := NewOptions(
+		WithHost("http://proxy"), AddHeader("Authorization", "Bearer base"), WithSecret("id", "key"),
+		WithHTTPClient(hc), WithValidator(acceptAll{}), WithStreamIdleTimeout(time.Second), WithSkipMalformedChunks(),
+		WithModelMapping(map[string]string{"a": "b"}), WithRoute("fast", Route{Model: "gpt-4o-mini"}),
+	)
+
+	// 同一服务商：继承全部设置，同名请求头被替换
+	d := base.Derive(AddHeader("Authorization", "Bearer route"), WithModelMapping(map[string]string{"c": "d"}))
+	if d.Host != "http://proxy" || d.SecretID != "id" || d.Header.Values("Authorization")[0] != "Bearer route" || len(d.Header.Values("Authorization")) != 1 {
+		t.Fatalf("same provider should inherit settings: %+v", d)
+	}
+	if len(d.ModelMapping) != 2 || len(base.ModelMapping) != 1 || d.Routes != nil || base.Header.Get("Authorization") != "Bearer base" {
+		t.Fatalf("base options should not be modified: %+v %+v", d, base)
+	}
+
+	// 其他服务商：只继承 HTTP 客户端、校验器与流式设置，地址使用默认地址
+	d = base.Derive(WithType(Zhipu), AddHeader("Authorization", "id.secret"))
+	if d.Host != "https://open.bigmodel.cn" || d.Header.Get("Authorization") != "id.secret" || d.SecretID != "" || d.ModelMapping != nil {
+		t.Fatalf("credentials should not leak to another provider: %+v", d)
+	}
+	if d.HTTPClient != hc || d.Validator == nil || d.StreamIdleTimeout != time.Second || !d.SkipMalformedChunks {
+		t.Fatalf("transport settings should be inherited: %+v", d)
+	}
+	if d = base.Derive(WithType(Hunyuan), WithHost("http://local")); d.Host != "http://local" {
+		t.Fatalf("explicit host should win, got %q", d.Host)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/config"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/sse"
+)
+
+// maxBodySize 是请求体的最大长度
+const maxBodySize = 32 << 20
+
+// provider 是网关使用的 uniai 能力，由 uniai.New 返回的实例实现
+type provider interface {
+	Completions(ctx context.Context, in request.Request) (chan response.Response, error)
+	Embeddings(ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error)
+	ListModels(ctx context.Context) (*response.ModelList, error)
+}
+
+// gateway 以 OpenAI 的接口格式对外提供服务，并按模型名称的前缀将请求转发给对应的服务商。
+// 例如模型 xfyun/generalv3 会以模型 generalv3 转发给名为 xfyun 的服务商。
+type gateway struct {
+	routes atomic.Pointer[routes]
+}
+
+// routes 是网关的路由表，配置文件变化后整体替换
+type routes struct {
+	providers map[string]provider // 以名称为键的服务商，名称即模型名称中的前缀
+	aliases   map[string]provider // 以别名为键的客户端，模型名称等于别名时使用别名配置的模型
+	fallback  string              // 模型名称无法匹配时使用的服务商或别名，为空时这类请求返回 404
+}
+
+// newGateway 使用路由表创建网关
+func newGateway(r *routes) *gateway {
+	g := &gateway{}
+	g.routes.Store(r)
+	return g
+}
+
+// newRoutes 根据配置创建路由表
+func newRoutes(cfg *config.Config) *routes {
+	r := &routes{providers: make(map[string]provider), aliases: make(map[string]provider), fallback: cfg.Default}
+	for name := range cfg.Providers {
+		r.providers[name], _ = cfg.Client(name)
+	}
+	for name := range cfg.Aliases {
+		r.aliases[name], _ = cfg.Client(name)
+	}
+	return r
+}
+
+// update 替换路由表，进行中的请求继续使用原来的客户端
+func (g *gateway) update(r *routes) {
+	g.routes.Store(r)
+}
+
+// handler 返回网关的路由
+func (g *gateway) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", g.completions)
+	mux.HandleFunc("POST /v1/embeddings", g.embeddings)
+	mux.HandleFunc("GET /v1/models", g.models)
+	return mux
+}
+
+// route 根据模型名称返回服务商及转发时使用的模型名称。
+// 模型名称依次按服务商前缀、别名与 fallback 匹配，匹配别名时模型名称仍为别名，由客户端解析为别名的模型与默认参数。
+func (g *gateway) route(model string) (provider, string, error) {
+	r := g.routes.Load()
+	if name, rest, ok := strings.Cut(model, "/"); ok {
+		if p, ok := r.providers[name]; ok {
+			return p, rest, nil
+		}
+	}
+	if p, ok := r.aliases[model]; ok {
+		return p, model, nil
+	}
+	if p, ok := r.providers[r.fallback]; ok {
+		return p, model, nil
+	}
+	if p, ok := r.aliases[r.fallback]; ok {
+		return p, model, nil
+	}
+	return nil, "", errors.Wrapf(errorx.NotFound, "model %q does not match any provider", model)
+}
+
+// completions 处理 /v1/chat/completions 请求，流式请求以 SSE 的形式输出
+func (g *gateway) completions(w http.ResponseWriter, r *http.Request) {
+	var in request.Request
+	if err := decode(w, r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	model := in.Model
+	p, name, err := g.route(model)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	in.Model = name
+
+	// 客户端断开连接时服务商的请求也会被取消
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	out, err := p.Completions(ctx, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if !in.Stream {
+		acc := response.NewAccumulator()
+		for resp := range out {
+			acc.Add(resp)
+		}
+		if err := acc.Err(); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp := acc.Response()
+		resp.Model = model
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	if err := sse.Write(w, r, response.NewStream(out, cancel), sse.WithModel(model)); err != nil {
+		slog.Warn("gateway stream error", slog.String("model", model), slog.Any("err", err))
+	}
+}
+
+// embeddingRequest 是 /v1/embeddings 的请求体，input 可以是字符串或字符串数组
+type embeddingRequest struct {
+	Model          string `json:"model"`
+	Input          any    `json:"input"`
+	Dimensions     *int   `json:"dimensions,omitempty"`
+	EncodingFormat string `json:"encoding_format,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+// embeddings 处理 /v1/embeddings 请求
+func (g *gateway) embeddings(w http.ResponseWriter, r *http.Request) {
+	var body embeddingRequest
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	in := request.EmbeddingRequest{Dimensions: body.Dimensions, EncodingFormat: body.EncodingFormat, User: body.User}
+	switch v := body.Input.(type) {
+	case string:
+		in.Input = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				writeError(w, errors.Wrap(errorx.InvalidInput, "input must be a string or an array of strings"))
+				return
+			}
+			in.Input = append(in.Input, s)
+		}
+	default:
+		writeError(w, errors.Wrap(errorx.InvalidInput, "input must be a string or an array of strings"))
+		return
+	}
+
+	p, name, err := g.route(body.Model)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	in.Model = name
+
+	resp, err := p.Embeddings(r.Context(), in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp.Model = body.Model
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// models 处理 /v1/models 请求，返回所有服务商的模型与别名，模型名称带有服务商前缀。
+// 获取失败的服务商会被跳过，以免一个服务商的故障影响整个列表。
+func (g *gateway) models(w http.ResponseWriter, r *http.Request) {
+	routes := g.routes.Load()
+	out := response.ModelList{Object: "list", Data: []response.Model{}}
+	for _, name := range sorted(routes.aliases) {
+		out.Data = append(out.Data, response.Model{ID: name, Object: "model", OwnedBy: "alias"})
+	}
+
+	for _, name := range sorted(routes.providers) {
+		list, err := routes.providers[name].ListModels(r.Context())
+		if err != nil {
+			slog.Warn("gateway list models error", slog.String("provider", name), slog.Any("err", err))
+			continue
+		}
+		for _, m := range list.Data {
+			m.ID = name + "/" + m.ID
+			if m.Object == "" {
+				m.Object = "model"
+			}
+			out.Data = append(out.Data, m)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// sorted 返回按字典序排列的键
+func sorted(m map[string]provider) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decode 解析 JSON 请求体
+func decode(w http.ResponseWriter, r *http.Request, v any) error {
+	if err := sonic.ConfigDefault.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v); err != nil {
+		return errors.Wrap(errorx.InvalidInput, "invalid request body: "+err.Error())
+	}
+	return nil
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := sonic.ConfigDefault.Marshal(v)
+	if err != nil {
+		status, data = http.StatusInternalServerError, []byte(`{"error":{"message":"marshal response failed","type":"api_error"}}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeError 以 OpenAI 的错误格式输出错误
+func writeError(w http.ResponseWriter, err error) {
+	status, body := sse.NewErrorBody(err)
+	writeJSON(w, status, body)
+}
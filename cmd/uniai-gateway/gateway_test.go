@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/config"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func post(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func Test_Gateway(t *testing.T) {
+	upstream := uniaitest.NewServer(t, uniaitest.FormatOpenAI, uniaitest.Text("你", "好"))
+	mock := uniaitest.NewClient(uniaitest.Text("ok"), uniaitest.Error(errorx.RateLimited))
+	g := newGateway(&routes{providers: map[string]provider{
+		"openai": uniai.New(client.WithType(client.OpenAI), client.WithHost(upstream.URL)),
+		"tongyi": uniai.New(client.WithType(client.Tongyi), client.WithClient(mock)),
+	}})
+	srv := httptest.NewServer(g.handler())
+	defer srv.Close()
+
+	status, body := post(t, srv.URL+"/v1/chat/completions", `{"model":"openai/gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	if status != http.StatusOK || !strings.Contains(body, `"content":"好"`) || !strings.Contains(body, `"model":"openai/gpt-4o"`) || !strings.HasSuffix(body, "data: [DONE]\n\n") {
+		t.Fatalf("stream: %d %s", status, body)
+	}
+	if in := upstream.Request(t, 0).Request(t); in.Model != "gpt-4o" {
+		t.Fatalf("upstream model = %q", in.Model)
+	}
+
+	status, body = post(t, srv.URL+"/v1/chat/completions", `{"model":"tongyi/qwen-max","messages":[{"role":"user","content":"hi"}]}`)
+	var resp response.Response
+	if err := sonic.ConfigDefault.UnmarshalFromString(body, &resp); err != nil || status != http.StatusOK || resp.Choices[0].Message.Content != "ok" {
+		t.Fatalf("completion: %d %s", status, body)
+	}
+	if model := mock.Requests()[0].Model; model != "qwen-max" {
+		t.Fatalf("provider model = %q", model)
+	}
+
+	if status, body = post(t, srv.URL+"/v1/chat/completions", `{"model":"tongyi/qwen-max","messages":[]}`); status != http.StatusTooManyRequests || !strings.Contains(body, "rate_limit_error") {
+		t.Fatalf("rate limited: %d %s", status, body)
+	}
+	if status, _ = post(t, srv.URL+"/v1/chat/completions", `{"model":"gpt-4o"}`); status != http.StatusNotFound {
+		t.Fatalf("unknown provider: %d", status)
+	}
+
+	status, body = post(t, srv.URL+"/v1/embeddings", `{"model":"tongyi/text-embedding-v3","input":"abc"}`)
+	if status != http.StatusOK || !strings.Contains(body, `"model":"tongyi/text-embedding-v3"`) || mock.EmbeddingRequests()[0].Input[0] != "abc" {
+		t.Fatalf("embeddings: %d %s", status, body)
+	}
+
+	models, err := http.Get(srv.URL + "/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer models.Body.Close()
+	var list response.ModelList
+	if err := sonic.ConfigDefault.NewDecoder(models.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, m := range list.Data {
+		found = found || strings.HasPrefix(m.ID, "tongyi/")
+	}
+	if !found {
+		t.Fatalf("models should contain tongyi models: %+v", list.Data)
+	}
+}
+
+func Test_GatewayAlias(t *testing.T) {
+	upstream := uniaitest.NewServer(t, uniaitest.FormatOpenAI, uniaitest.Text("ok"))
+	cfg, err := config.Parse([]byte(`{
+		"default": "main",
+		"providers": {"main": {"type": "openai", "host": "`+upstream.URL+`", "model": "gpt-4o"}},
+		"aliases": {
+			"fast": {"provider": "main", "model": "gpt-4o-mini", "temperature": 0.2},
+			"embed": {"provider": "main", "model": "text-embedding-3-small"}
+		}
+	}`), ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(newGateway(newRoutes(cfg)).handler())
+	defer srv.Close()
+
+	if status, body := post(t, srv.URL+"/v1/chat/completions", `{"model":"fast","messages":[{"role":"user","content":"hi"}]}`); status != http.StatusOK || !strings.Contains(body, `"model":"fast"`) {
+		t.Fatalf("completion: %d %s", status, body)
+	}
+	if in := upstream.Request(t, 0).Request(t); in.Model != "gpt-4o-mini" || in.Temperature == nil || *in.Temperature != 0.2 {
+		t.Fatalf("alias should use its model and params: %+v", in)
+	}
+
+	if status, body := post(t, srv.URL+"/v1/embeddings", `{"model":"embed","input":"abc"}`); status != http.StatusOK || !strings.Contains(body, `"model":"embed"`) {
+		t.Fatalf("embeddings: %d %s", status, body)
+	}
+	if model := upstream.Request(t, 1).Request(t).Model; model != "text-embedding-3-small" {
+		t.Fatalf("upstream embedding model = %q", model)
+	}
+}
@@ -0,0 +1,71 @@
+// Command uniai-gateway 是兼容 OpenAI 接口的网关，它对外提供 /v1/chat/completions、/v1/embeddings 与 /v1/models，
+// 并按模型名称的前缀（如 xfyun/generalv3、tongyi/qwen-max）将请求转发给配置的服务商。
+//
+// 服务商与模型别名使用 config 包的配置文件，模型名称等于别名时按别名转发；配置文件变化后会自动重新读取。
+// 配置文件示例：
+//
+//	{
+//	  "default": "openai",
+//	  "providers": {
+//	    "openai": {"type": "openai", "token": "Bearer ${OPENAI_API_KEY}"},
+//	    "xfyun": {"type": "xfyun", "token": "Bearer ${XFYUN_TOKEN}"},
+//	    "tongyi": {"type": "tongyi", "token": "Bearer ${DASHSCOPE_API_KEY}"}
+//	  },
+//	  "aliases": {
+//	    "fast": {"provider": "tongyi", "model": "qwen-turbo"}
+//	  }
+//	}
+//
+// 用法：
+//
+//	uniai-gateway -addr :8080 -config gateway.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jun3372/uniai/config"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	path := flag.String("config", "gateway.json", "config file")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	g := newGateway(&routes{})
+	watcher, err := config.Watch(ctx, *path, config.WithOnReload(func(cfg *config.Config, err error) {
+		if err == nil {
+			g.update(newRoutes(cfg))
+		}
+	}))
+	if err != nil {
+		slog.Error("load config error", slog.Any("err", err))
+		os.Exit(1)
+	}
+	g.update(newRoutes(watcher.Config()))
+
+	srv := &http.Server{Addr: *addr, Handler: g.handler()}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	slog.Info("uniai gateway listening", slog.String("addr", *addr), slog.Any("names", watcher.Config().Names()))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("gateway serve error", slog.Any("err", err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/jun3372/uniai"
+)
+
+// fence 是多行输入的起止标记
+const fence = `"""`
+
+// chatHelp 是会话中可用的命令
+const chatHelp = `commands:
+  /system <prompt>  set the system prompt
+  /history          print the history
+  /reset            clear the history
+  /exit             quit
+multi-line input: end a line with \ to continue, or wrap the message in """ lines.
+`
+
+// runChat 运行交互式会话。提示符与命令的输出写入 stderr，回复写入 stdout；
+// 回复过程中按 Ctrl-C 只会中断本次回复，不完整的回复不会进入历史。
+func runChat(s *settings, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := []uniai.ConversationOption{uniai.WithRequestOptions(s.requestOptions()...)}
+	if s.System != "" {
+		opts = append(opts, uniai.WithSystemPrompt(s.System))
+	}
+	conv := uniai.NewConversation(newClient(s), opts...)
+
+	r := bufio.NewReader(stdin)
+	for {
+		fmt.Fprint(stderr, "> ")
+		msg, err := readMessage(r, stderr)
+		if msg = strings.TrimSpace(msg); msg != "" {
+			if quit := chat(s, conv, msg, stdout, stderr); quit {
+				return 0
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(stderr)
+			return 0
+		}
+	}
+}
+
+// chat 处理一条输入，输入为退出命令时返回 true
+func chat(s *settings, conv *uniai.Conversation, msg string, stdout, stderr io.Writer) bool {
+	switch cmd, arg, _ := strings.Cut(msg, " "); cmd {
+	case "/exit", "/quit":
+		return true
+	case "/help":
+		fmt.Fprint(stderr, chatHelp)
+		return false
+	case "/reset":
+		conv.Reset()
+		fmt.Fprintln(stderr, "history cleared")
+		return false
+	case "/system":
+		conv.SetSystemPrompt(strings.TrimSpace(arg))
+		fmt.Fprintln(stderr, "system prompt updated")
+		return false
+	case "/history":
+		for _, m := range conv.Messages() {
+			fmt.Fprintf(stderr, "%s: %s\n", m.Role, m.Content)
+		}
+		return false
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := newContext(ctx, s)
+	defer cancel()
+
+	out, err := conv.Send(ctx, msg)
+	if err == nil {
+		err = render(stdout, stderr, s.Output, out)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+	}
+	return false
+}
+
+// readMessage 读取一条消息。以 \ 结尾的行会与下一行合并，""" 之间的多行内容作为一条消息；
+// 输入结束时返回已读取的内容与 io.EOF。
+func readMessage(r *bufio.Reader, prompt io.Writer) (string, error) {
+	line, err := readLine(r)
+	if strings.TrimSpace(line) == fence {
+		var lines []string
+		for err == nil {
+			fmt.Fprint(prompt, "... ")
+			if line, err = readLine(r); strings.TrimSpace(line) == fence {
+				break
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n"), err
+	}
+
+	var b strings.Builder
+	for err == nil && strings.HasSuffix(line, `\`) {
+		b.WriteString(strings.TrimSuffix(line, `\`) + "\n")
+		fmt.Fprint(prompt, "... ")
+		line, err = readLine(r)
+	}
+	b.WriteString(line)
+	return b.String(), err
+}
+
+// readLine 读取一行并去掉换行符
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
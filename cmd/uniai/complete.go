@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/request"
+)
+
+// runComplete 执行单次补全，未指定 prompt 或 prompt 为 - 时从标准输入读取
+func runComplete(s *settings, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	prompt := strings.Join(args, " ")
+	if prompt == "" || prompt == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, "read stdin:", err)
+			return 1
+		}
+		prompt = strings.TrimSpace(string(data))
+	}
+	if prompt == "" {
+		fmt.Fprintln(stderr, "empty prompt")
+		return 2
+	}
+
+	var messages []request.Messages
+	if s.System != "" {
+		messages = append(messages, request.NewSystemMessage(s.System))
+	}
+	messages = append(messages, request.NewUserMessage(prompt))
+	in := *request.NewRequest(request.WithMessages(messages))
+	for _, opt := range s.requestOptions() {
+		opt(&in)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := newContext(ctx, s)
+	defer cancel()
+
+	out, err := newClient(s).Completions(ctx, in)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if err := render(stdout, stderr, s.Output, out); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// runModels 列出服务提供的模型，文本格式下每行输出一个模型名称
+func runModels(s *settings, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	ctx, cancel := newContext(context.Background(), s)
+	defer cancel()
+
+	list, err := newClient(s).ListModels(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	switch s.Output {
+	case outputJSON:
+		data, _ := sonic.ConfigDefault.MarshalIndent(list, "", "  ")
+		fmt.Fprintln(stdout, string(data))
+	case outputJSONL:
+		for _, m := range list.Data {
+			data, _ := sonic.ConfigDefault.MarshalToString(m)
+			fmt.Fprintln(stdout, data)
+		}
+	default:
+		for _, m := range list.Data {
+			fmt.Fprintln(stdout, m.ID)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+// Command uniai 是基于 uniai 客户端的命令行工具，用于快速验证服务商的接口。
+//
+// 用法：
+//
+//	uniai chat [flags]               交互式会话，保留历史消息并流式输出回复
+//	uniai complete [flags] [prompt]  单次补全，未指定 prompt 时从标准输入读取
+//	uniai models [flags]             列出服务提供的模型
+//
+// 服务商类型、地址、令牌、模型与采样参数可以通过 UNIAI_TYPE、UNIAI_HOST、UNIAI_TOKEN、UNIAI_MODEL、UNIAI_TEMPERATURE 等环境变量设置，
+// 例如：
+//
+//	export UNIAI_TYPE=tongyi UNIAI_TOKEN="Bearer sk-xxx" UNIAI_MODEL=qwen-max
+//	echo "写一首关于春天的诗" | uniai complete -temperature 0.8
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/response"
+)
+
+// ai 是命令行工具使用的 uniai 能力，由 uniai.New 返回的实例实现
+type ai interface {
+	uniai.Completer
+	ListModels(ctx context.Context) (*response.ModelList, error)
+}
+
+// commands 是所有子命令，函数返回进程的退出码
+var commands = map[string]func(s *settings, args []string, stdin io.Reader, stdout, stderr io.Writer) int{
+	"chat":     runChat,
+	"complete": runComplete,
+	"models":   runModels,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 解析子命令与参数并执行，返回进程的退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || commands[args[0]] == nil {
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("uniai "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	s := newSettings(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if err := s.validate(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	return commands[args[0]](s, fs.Args(), stdin, stdout, stderr)
+}
+
+// newClient 根据参数创建客户端
+func newClient(s *settings) ai {
+	return uniai.New(s.clientOptions()...)
+}
+
+// newContext 返回设置了超时时间的上下文
+func newContext(parent context.Context, s *settings) (context.Context, context.CancelFunc) {
+	if s.Timeout > 0 {
+		return context.WithTimeout(parent, s.Timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: uniai <command> [flags]
+
+commands:
+  chat      interactive chat with history and streaming output
+  complete  one-shot completion, reads the prompt from stdin when no argument is given
+  models    list models of the provider
+
+run "uniai <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func Test_Run(t *testing.T) {
+	srv := uniaitest.NewServer(t, uniaitest.FormatOpenAI, uniaitest.Text("你", "好"), uniaitest.Text("ok"), uniaitest.Text("第一轮"), uniaitest.Text("第二轮"), uniaitest.Text("env"))
+	flags := []string{"-host", srv.URL, "-token", "Bearer sk", "-model", "gpt-4o"}
+	exec := func(stdin string, args ...string) (string, string, int) {
+		var stdout, stderr strings.Builder
+		code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+		return stdout.String(), stderr.String(), code
+	}
+
+	stdout, stderr, code := exec("写一首诗\n", append([]string{"complete", "-temperature", "0"}, flags...)...)
+	if code != 0 || stdout != "你好\n" {
+		t.Fatalf("complete: %d %q %q", code, stdout, stderr)
+	}
+	in := srv.Request(t, 0).Request(t)
+	if in.Messages[0].Content != "写一首诗" || in.Temperature == nil || *in.Temperature != 0 || in.TopP != nil {
+		t.Fatalf("unexpected request: %+v", in)
+	}
+	srv.Request(t, 0).AssertHeader(t, "Authorization", "Bearer sk")
+
+	stdout, _, code = exec("", append([]string{"complete", "-output", "jsonl", "-stream=false"}, append(flags, "hi")...)...)
+	if code != 0 || strings.Count(stdout, "\n") != 1 || !strings.Contains(stdout, `"content":"ok"`) {
+		t.Fatalf("jsonl: %d %q", code, stdout)
+	}
+
+	stdout, stderr, code = exec("\"\"\"\n第一行\n第二行\n\"\"\"\n继续\\\n输入\n/exit\n", append([]string{"chat"}, flags...)...)
+	if code != 0 || stdout != "第一轮\n第二轮\n" {
+		t.Fatalf("chat: %d %q %q", code, stdout, stderr)
+	}
+	second := srv.Request(t, 3).Request(t)
+	if len(second.Messages) != 3 || second.Messages[0].Content != "第一行\n第二行" || second.Messages[2].Content != "继续\n输入" {
+		t.Fatalf("chat should keep history: %+v", second.Messages)
+	}
+
+	// 环境变量中的采样参数同样会发送，命令行中的值优先
+	t.Setenv("UNIAI_TOP_P", "0.5")
+	t.Setenv("UNIAI_MAX_COMPLETION_TOKENS", "64")
+	if _, stderr, code = exec("", append([]string{"complete", "-top-p", "0.9", "-stream=false"}, append(flags, "hi")...)...); code != 0 {
+		t.Fatalf("env: %d %q", code, stderr)
+	}
+	if in := srv.Request(t, 4).Request(t); in.TopP == nil || *in.TopP != 0.9 || in.MaxCompletionTokens == nil || *in.MaxCompletionTokens != 64 || in.Temperature != nil {
+		t.Fatalf("unexpected request: %+v", in)
+	}
+
+	if _, _, code = exec("", "complete", "-output", "xml", "hi"); code != 2 {
+		t.Fatalf("invalid output format should fail, got %d", code)
+	}
+	if stdout, _, code = exec("", "models", "-type", "zhipu"); code != 0 || !strings.Contains(stdout, "glm") {
+		t.Fatalf("models: %d %q", code, stdout)
+	}
+	t.Setenv("UNIAI_TEMPERATURE", "hot")
+	if _, _, code = exec("", append([]string{"complete"}, append(flags, "hi")...)...); code != 2 {
+		t.Fatalf("invalid env value should fail, got %d", code)
+	}
+}
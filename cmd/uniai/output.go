@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/response"
+)
+
+// render 按输出格式输出回复的分片，返回回复中的错误。
+// 文本格式下思考过程输出到 errw，回复内容输出到 w，json 格式在回复结束后输出归并后的完整响应。
+func render(w, errw io.Writer, format string, out <-chan response.Response) error {
+	acc := response.NewAccumulator()
+	var last string
+	for resp := range out {
+		acc.Add(resp)
+		if resp.Err != nil {
+			break
+		}
+
+		switch format {
+		case outputJSONL:
+			data, _ := sonic.ConfigDefault.MarshalToString(resp)
+			fmt.Fprintln(w, data)
+		case outputText:
+			reasoning, content := text(resp)
+			io.WriteString(errw, reasoning)
+			io.WriteString(w, content)
+			if content != "" {
+				last = content
+			}
+		}
+	}
+
+	switch format {
+	case outputJSON:
+		data, _ := sonic.ConfigDefault.MarshalIndent(acc.Response(), "", "  ")
+		fmt.Fprintln(w, string(data))
+	case outputText:
+		if last != "" && !strings.HasSuffix(last, "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+	return acc.Err()
+}
+
+// text 返回分片中第一个选项的思考过程与回复内容，兼容流式分片与完整响应
+func text(resp response.Response) (string, string) {
+	if len(resp.Choices) == 0 {
+		return "", ""
+	}
+	if c := resp.Choices[0]; c.Delta != nil {
+		return c.Delta.ReasoningContent, c.Delta.Content
+	} else if c.Message != nil {
+		return c.Message.ReasoningContent, c.Message.Content
+	}
+	return "", ""
+}
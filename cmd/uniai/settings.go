@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+// 输出格式
+const (
+	outputText  = "text"  // 以文本形式输出回复，流式请求逐段输出
+	outputJSON  = "json"  // 输出归并后的完整响应
+	outputJSONL = "jsonl" // 每行输出一个分片
+)
+
+// samplingEnv 是采样参数对应的环境变量
+var samplingEnv = map[string]string{
+	"temperature":           "UNIAI_TEMPERATURE",
+	"top-p":                 "UNIAI_TOP_P",
+	"max-tokens":            "UNIAI_MAX_TOKENS",
+	"max-completion-tokens": "UNIAI_MAX_COMPLETION_TOKENS",
+	"frequency-penalty":     "UNIAI_FREQUENCY_PENALTY",
+	"presence-penalty":      "UNIAI_PRESENCE_PENALTY",
+	"stop":                  "UNIAI_STOP",
+	"reasoning-effort":      "UNIAI_REASONING_EFFORT",
+}
+
+// settings 是各子命令共用的参数，未通过命令行指定时从 UNIAI_ 开头的环境变量读取
+type settings struct {
+	fs     *flag.FlagSet
+	envErr error // 环境变量中的采样参数无法解析时的错误
+
+	Type      string
+	Host      string
+	Token     string
+	SecretID  string
+	SecretKey string
+	Region    string
+	Model     string
+	Output    string
+	Timeout   time.Duration
+
+	System              string
+	Stream              bool
+	Temperature         float64
+	TopP                float64
+	MaxTokens           int
+	MaxCompletionTokens int
+	FrequencyPenalty    float64
+	PresencePenalty     float64
+	Stop                string
+	ReasoningEffort     string
+}
+
+// newSettings 在 fs 上注册共用的参数
+func newSettings(fs *flag.FlagSet) *settings {
+	s := &settings{fs: fs}
+	fs.StringVar(&s.Type, "type", env("UNIAI_TYPE", client.OpenAI), "provider type, such as openai, xfyun, tongyi (env UNIAI_TYPE)")
+	fs.StringVar(&s.Host, "host", os.Getenv("UNIAI_HOST"), "api host, empty for the public endpoint of the provider type (env UNIAI_HOST)")
+	fs.StringVar(&s.Token, "token", os.Getenv("UNIAI_TOKEN"), "value of the Authorization header (env UNIAI_TOKEN)")
+	fs.StringVar(&s.SecretID, "secret-id", os.Getenv("UNIAI_SECRET_ID"), "secret id for signed requests (env UNIAI_SECRET_ID)")
+	fs.StringVar(&s.SecretKey, "secret-key", os.Getenv("UNIAI_SECRET_KEY"), "secret key for signed requests (env UNIAI_SECRET_KEY)")
+	fs.StringVar(&s.Region, "region", os.Getenv("UNIAI_REGION"), "service region (env UNIAI_REGION)")
+	fs.StringVar(&s.Model, "model", os.Getenv("UNIAI_MODEL"), "model name (env UNIAI_MODEL)")
+	fs.StringVar(&s.Output, "output", env("UNIAI_OUTPUT", outputText), "output format: text, json or jsonl (env UNIAI_OUTPUT)")
+	fs.DurationVar(&s.Timeout, "timeout", 0, "request timeout, 0 for no timeout")
+
+	fs.StringVar(&s.System, "system", "", "system prompt")
+	fs.BoolVar(&s.Stream, "stream", true, "stream the reply")
+	fs.Float64Var(&s.Temperature, "temperature", 0, "sampling temperature (env UNIAI_TEMPERATURE)")
+	fs.Float64Var(&s.TopP, "top-p", 0, "nucleus sampling probability (env UNIAI_TOP_P)")
+	fs.IntVar(&s.MaxTokens, "max-tokens", 0, "maximum number of tokens to generate (env UNIAI_MAX_TOKENS)")
+	fs.IntVar(&s.MaxCompletionTokens, "max-completion-tokens", 0, "maximum number of tokens to generate, including reasoning tokens (env UNIAI_MAX_COMPLETION_TOKENS)")
+	fs.Float64Var(&s.FrequencyPenalty, "frequency-penalty", 0, "frequency penalty (env UNIAI_FREQUENCY_PENALTY)")
+	fs.Float64Var(&s.PresencePenalty, "presence-penalty", 0, "presence penalty (env UNIAI_PRESENCE_PENALTY)")
+	fs.StringVar(&s.Stop, "stop", "", "comma separated stop sequences (env UNIAI_STOP)")
+	fs.StringVar(&s.ReasoningEffort, "reasoning-effort", "", "reasoning effort: low, medium or high (env UNIAI_REASONING_EFFORT)")
+
+	// 环境变量中的采样参数视同在命令行中指定，命令行中的值会覆盖它们
+	fs.VisitAll(func(f *flag.Flag) {
+		key, ok := samplingEnv[f.Name]
+		if !ok {
+			return
+		}
+		if v, ok := os.LookupEnv(key); ok && s.envErr == nil {
+			if err := fs.Set(f.Name, v); err != nil {
+				s.envErr = errors.Wrapf(errorx.InvalidInput, "invalid %s %q: %v", key, v, err)
+			}
+		}
+	})
+	return s
+}
+
+// validate 校验参数
+func (s *settings) validate() error {
+	if s.envErr != nil {
+		return s.envErr
+	}
+	switch s.Output {
+	case outputText, outputJSON, outputJSONL:
+		return nil
+	default:
+		return errors.Wrapf(errorx.InvalidInput, "unknown output format %q", s.Output)
+	}
+}
+
+// clientOptions 返回创建客户端的选项
+func (s *settings) clientOptions() []client.Option {
+	opts := []client.Option{client.WithType(s.Type)}
+	if s.Host != "" {
+		opts = append(opts, client.WithHost(s.Host))
+	}
+	if s.Token != "" {
+		opts = append(opts, client.AddHeader("Authorization", s.Token))
+	}
+	if s.SecretID != "" || s.SecretKey != "" {
+		opts = append(opts, client.WithSecret(s.SecretID, s.SecretKey))
+	}
+	if s.Region != "" {
+		opts = append(opts, client.WithRegion(s.Region))
+	}
+	return opts
+}
+
+// requestOptions 返回请求的选项，采样参数只有在命令行或环境变量中指定时才会发送
+func (s *settings) requestOptions() []request.Option {
+	opts := []request.Option{request.WithModel(s.Model), request.WithStream(s.Stream)}
+	s.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "temperature":
+			opts = append(opts, request.WithTemperature(float32(s.Temperature)))
+		case "top-p":
+			opts = append(opts, request.WithTopP(float32(s.TopP)))
+		case "max-tokens":
+			opts = append(opts, request.WithMaxTokens(s.MaxTokens))
+		case "max-completion-tokens":
+			opts = append(opts, request.WithMaxCompletionTokens(s.MaxCompletionTokens))
+		case "frequency-penalty":
+			opts = append(opts, request.WithFrequencyPenalty(float32(s.FrequencyPenalty)))
+		case "presence-penalty":
+			opts = append(opts, request.WithPresencePenalty(float32(s.PresencePenalty)))
+		case "stop":
+			opts = append(opts, request.WithStop(strings.Split(s.Stop, ",")))
+		case "reasoning-effort":
+			opts = append(opts, request.WithReasoningEffort(s.ReasoningEffort))
+		}
+	})
+	return opts
+}
+
+// env 返回环境变量的值，未设置时返回 def
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Client 是由配置创建的客户端，与 uniai.New 返回的实例具有相同的方法
+type Client interface {
+	Completions(ctx context.Context, in request.Request) (chan response.Response, error)
+	Stream(ctx context.Context, in request.Request) (*response.Stream, error)
+	Embeddings(ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error)
+	Images() uniai.Images
+	Audio() uniai.Audio
+	ListModels(ctx context.Context) (*response.ModelList, error)
+}
+
+// modelClient 在请求未指定模型时使用默认模型
+type modelClient struct {
+	Client
+	model string
+}
+
+// withModel 返回在请求未指定模型时使用 model 的客户端，model 为空时直接返回 c
+func withModel(c Client, model string) Client {
+	if model == "" {
+		return c
+	}
+	return &modelClient{Client: c, model: model}
+}
+
+func (c *modelClient) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	if in.Model == "" {
+		in.Model = c.model
+	}
+	return c.Client.Completions(ctx, in)
+}
+
+func (c *modelClient) Stream(ctx context.Context, in request.Request) (*response.Stream, error) {
+	if in.Model == "" {
+		in.Model = c.model
+	}
+	return c.Client.Stream(ctx, in)
+}
@@ -0,0 +1,388 @@
+// Package config 从 JSON 配置文件中读取命名的服务商与模型别名，并据此创建可以直接使用的客户端。
+// 其他格式（如 YAML）可以通过 WithDecoder 按扩展名注册解码器后使用。
+// 配置中的字符串可以使用 ${ENV} 或 ${ENV:-default} 引用环境变量，通常用于填写令牌与密钥，其余的 $ 原样保留。
+//
+// 配置文件示例：
+//
+//	{
+//	  "default": "tongyi",
+//	  "providers": {
+//	    "tongyi": {
+//	      "type": "tongyi",
+//	      "token": "Bearer ${DASHSCOPE_API_KEY}",
+//	      "model": "qwen-max",
+//	      "timeout": "30s",
+//	      "retry": {"max_attempts": 3, "backoff": "500ms"}
+//	    },
+//	    "hunyuan": {"type": "hunyuan", "secret_id": "${TENCENT_SECRET_ID}", "secret_key": "${TENCENT_SECRET_KEY}"}
+//	  },
+//	  "aliases": {
+//	    "fast": {"provider": "tongyi", "model": "qwen-turbo", "temperature": 0.3}
+//	  }
+//	}
+//
+// 别名会被设置为每个服务商客户端的 client.Route，因此任一客户端都可以使用别名作为模型名称，
+// 修改别名指向的服务商或模型后，调用方无需修改代码。
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+// types 是支持的服务商类型
+var types = []string{client.OpenAI, client.Tongyi, client.Xfyun, client.Zhipu, client.Hunyuan, client.Ark, client.Baidubce}
+
+// Config 是解析并校验后的配置
+type Config struct {
+	Default   string              `json:"default"`   // 默认的服务商或别名，调用方未指定名称时使用
+	Providers map[string]Provider `json:"providers"` // 以名称为键的服务商
+	Aliases   map[string]Alias    `json:"aliases"`   // 以别名为键的模型别名
+
+	clients map[string]Client
+}
+
+// Provider 是一个服务商的配置
+type Provider struct {
+	Type              string            `json:"type"`                // 服务商类型，如 openai、xfyun，必填
+	Host              string            `json:"host"`                // 接口地址，为空时使用服务商公开接口的默认地址，见 client.DefaultHost
+	Token             string            `json:"token"`               // Authorization 请求头的值
+	Header            map[string]string `json:"header"`              // 其他请求头
+	SecretID          string            `json:"secret_id"`           // 用于请求签名的 SecretId
+	SecretKey         string            `json:"secret_key"`          // 用于请求签名的 SecretKey
+	Region            string            `json:"region"`              // 服务所在的地域
+	Model             string            `json:"model"`               // 默认模型，请求未指定模型时使用
+	ModelMapping      map[string]string `json:"model_mapping"`       // 模型名称映射
+	Timeout           Duration          `json:"timeout"`             // 等待响应头的最长时间，不包含读取流式响应的时间，0 表示不限制
+	StreamIdleTimeout Duration          `json:"stream_idle_timeout"` // 流式响应中两个分片之间的最长等待时间，0 表示不限制
+	Retry             *Retry            `json:"retry"`               // 重试策略，为空时不重试
+}
+
+// Retry 是请求失败时的重试策略，网络错误、429 与 5xx 响应会被重试。
+// 重试只发生在收到响应头之前，已经开始输出的流式响应不会被重试。
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts"` // 最多尝试的次数，包含第一次请求，默认为 3
+	Backoff     Duration `json:"backoff"`      // 第一次重试前的等待时间，之后每次翻倍，默认为 500 毫秒
+	MaxBackoff  Duration `json:"max_backoff"`  // 两次重试之间的最长等待时间，默认为 10 秒
+}
+
+// Alias 是模型别名，指向一个服务商的模型及默认的请求参数
+type Alias struct {
+	Provider        string   `json:"provider"`         // 服务商名称，必须是 providers 中的名称
+	Model           string   `json:"model"`            // 模型名称
+	Temperature     *float32 `json:"temperature"`      // 默认的采样温度，请求中未设置时使用
+	TopP            *float32 `json:"top_p"`            // 默认的 TopP，请求中未设置时使用
+	MaxTokens       *int     `json:"max_tokens"`       // 默认的最大生成标记数，请求中未设置时使用
+	ReasoningEffort string   `json:"reasoning_effort"` // 默认的思考强度，请求中未设置时使用
+}
+
+// route 将别名转换为 client.Route
+func (a Alias) route(p Provider) client.Route {
+	var params []request.Option
+	if a.Temperature != nil {
+		params = append(params, request.WithTemperature(*a.Temperature))
+	}
+	if a.TopP != nil {
+		params = append(params, request.WithTopP(*a.TopP))
+	}
+	if a.MaxTokens != nil {
+		params = append(params, request.WithMaxTokens(*a.MaxTokens))
+	}
+	if a.ReasoningEffort != "" {
+		params = append(params, request.WithReasoningEffort(a.ReasoningEffort))
+	}
+	return client.Route{Options: p.Options(), Model: a.Model, Params: params}
+}
+
+// Duration 是可以使用 "30s"、"1m" 等字符串或以秒为单位的数字配置的时长
+type Duration time.Duration
+
+// UnmarshalJSON 解析字符串或数字形式的时长
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := sonic.ConfigDefault.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+		*d = 0
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+	default:
+		return errors.Errorf("invalid duration %s", data)
+	}
+	return nil
+}
+
+// MarshalJSON 以字符串的形式输出时长
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Duration(d).String() + `"`), nil
+}
+
+// Decoder 将配置文件的内容解析到 v 中，如 yaml.Unmarshal
+type Decoder func(data []byte, v any) error
+
+// Options 结构体定义了读取配置的选项
+type Options struct {
+	Decoders map[string]Decoder           // 以文件扩展名为键的解析函数，.json 文件默认使用 JSON 解析
+	Interval time.Duration                // Watch 检查配置文件是否变化的间隔，默认为 2 秒
+	OnReload func(cfg *Config, err error) // Watch 重新读取配置后的回调，读取失败时 cfg 为 nil 且继续使用原配置
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// NewOptions 创建一个新的 Options 实例
+func NewOptions(opts ...Option) *Options {
+	o := &Options{Decoders: map[string]Decoder{".json": sonic.ConfigStd.Unmarshal}, Interval: 2 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDecoder 设置指定扩展名的配置文件使用的解析函数。
+// 本模块不依赖 YAML 库，读取 YAML 文件时需要传入解析函数，如 WithDecoder(".yaml", yaml.Unmarshal)。
+func WithDecoder(ext string, decode Decoder) Option {
+	return func(o *Options) { o.Decoders[strings.ToLower(ext)] = decode }
+}
+
+// WithInterval 设置 Watch 检查配置文件是否变化的间隔
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) { o.Interval = d }
+}
+
+// WithOnReload 设置 Watch 重新读取配置后的回调
+func WithOnReload(fn func(cfg *Config, err error)) Option {
+	return func(o *Options) { o.OnReload = fn }
+}
+
+// Load 读取并校验配置文件，文件格式由扩展名决定
+func Load(path string, opts ...Option) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "config: read file")
+	}
+	return Parse(data, filepath.Ext(path), opts...)
+}
+
+// Parse 解析并校验配置，ext 是配置的格式对应的扩展名，如 .json、.yaml。
+// 配置中引用的环境变量在解析时展开，未设置且没有默认值的环境变量会导致校验失败。
+func Parse(data []byte, ext string, opts ...Option) (*Config, error) {
+	return parse(data, ext, NewOptions(opts...))
+}
+
+func parse(data []byte, ext string, o *Options) (*Config, error) {
+	decode, ok := o.Decoders[strings.ToLower(ext)]
+	if !ok {
+		return nil, errors.Wrapf(errorx.NotSupported, "config: no decoder for %q files, set one with WithDecoder", ext)
+	}
+
+	// 先解析为通用的结构以展开环境变量，再转换为 Config，使各格式只需要支持基本类型
+	var tree any
+	if err := decode(data, &tree); err != nil {
+		return nil, errors.Wrap(errorx.InvalidInput, "config: parse: "+err.Error())
+	}
+	verr := &ValidationError{}
+	tree = expand(tree, "", verr)
+
+	normalized, err := sonic.ConfigStd.Marshal(tree)
+	if err != nil {
+		return nil, errors.Wrap(errorx.InvalidInput, "config: parse: "+err.Error())
+	}
+	var cfg Config
+	if err := sonic.ConfigStd.Unmarshal(normalized, &cfg); err != nil {
+		return nil, errors.Wrap(errorx.InvalidInput, "config: parse: "+err.Error())
+	}
+
+	cfg.validate(verr)
+	if len(verr.Problems) > 0 {
+		return nil, verr
+	}
+	cfg.build()
+	return &cfg, nil
+}
+
+// ValidationError 表示配置没有通过校验，Problems 中的每一项都以字段路径开头。
+// 它可以通过 errors.Is(err, errorx.InvalidInput) 判断。
+type ValidationError struct {
+	Problems []string
+}
+
+// Error 实现 error 接口，列出所有问题
+func (e *ValidationError) Error() string {
+	return "config: invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Unwrap 返回 InvalidInput，使 errors.Is(err, errorx.InvalidInput) 判断生效
+func (e *ValidationError) Unwrap() error {
+	return errorx.InvalidInput
+}
+
+func (e *ValidationError) add(path, format string, args ...any) {
+	e.Problems = append(e.Problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+// validate 校验配置，问题按字段路径排序后记录到 verr 中
+func (c *Config) validate(verr *ValidationError) {
+	if len(c.Providers) == 0 {
+		verr.add("providers", "at least one provider is required")
+	}
+	for name, p := range c.Providers {
+		path := "providers." + name
+		switch {
+		case p.Type == "":
+			verr.add(path+".type", "required")
+		case !contains(types, strings.ToLower(p.Type)):
+			verr.add(path+".type", "unknown provider type %q, want one of %s", p.Type, strings.Join(types, ", "))
+		}
+		if p.Host != "" {
+			if u, err := url.Parse(p.Host); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				verr.add(path+".host", "invalid url %q", p.Host)
+			}
+		}
+		if p.Timeout < 0 {
+			verr.add(path+".timeout", "must not be negative")
+		}
+		if p.StreamIdleTimeout < 0 {
+			verr.add(path+".stream_idle_timeout", "must not be negative")
+		}
+		if r := p.Retry; r != nil && (r.MaxAttempts < 0 || r.Backoff < 0 || r.MaxBackoff < 0) {
+			verr.add(path+".retry", "values must not be negative")
+		}
+	}
+
+	for name, a := range c.Aliases {
+		path := "aliases." + name
+		if _, ok := c.Providers[name]; ok {
+			verr.add(path, "conflicts with the provider of the same name")
+		}
+		if _, ok := c.Providers[a.Provider]; !ok {
+			verr.add(path+".provider", "unknown provider %q", a.Provider)
+		}
+		if a.Model == "" {
+			verr.add(path+".model", "required")
+		}
+	}
+
+	if c.Default != "" {
+		_, isProvider := c.Providers[c.Default]
+		_, isAlias := c.Aliases[c.Default]
+		if !isProvider && !isAlias {
+			verr.add("default", "unknown provider or alias %q", c.Default)
+		}
+	}
+	sort.Strings(verr.Problems)
+}
+
+// Options 返回创建指定服务商的客户端使用的选项
+func (p Provider) Options() []client.Option {
+	opts := []client.Option{client.WithType(strings.ToLower(p.Type))}
+	if p.Host != "" {
+		opts = append(opts, client.WithHost(p.Host))
+	}
+	if p.Token != "" {
+		opts = append(opts, client.AddHeader("Authorization", p.Token))
+	}
+	for k, v := range p.Header {
+		opts = append(opts, client.AddHeader(k, v))
+	}
+	if p.SecretID != "" || p.SecretKey != "" {
+		opts = append(opts, client.WithSecret(p.SecretID, p.SecretKey))
+	}
+	if p.Region != "" {
+		opts = append(opts, client.WithRegion(p.Region))
+	}
+	if len(p.ModelMapping) > 0 {
+		opts = append(opts, client.WithModelMapping(p.ModelMapping))
+	}
+	if p.StreamIdleTimeout > 0 {
+		opts = append(opts, client.WithStreamIdleTimeout(time.Duration(p.StreamIdleTimeout)))
+	}
+	if p.Timeout > 0 || p.Retry != nil {
+		opts = append(opts, client.WithHTTPClient(&http.Client{Transport: p.transport()}))
+	}
+	return opts
+}
+
+// transport 返回设置了超时与重试策略的 http.RoundTripper
+func (p Provider) transport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ResponseHeaderTimeout = time.Duration(p.Timeout)
+	if p.Retry == nil {
+		return t
+	}
+	return newRetryTransport(t, *p.Retry)
+}
+
+// build 为每个服务商与别名创建客户端，所有别名都会被设置为每个服务商客户端的路由
+func (c *Config) build() {
+	routes := make(map[string]client.Route, len(c.Aliases))
+	for name, a := range c.Aliases {
+		routes[name] = a.route(c.Providers[a.Provider])
+	}
+
+	c.clients = make(map[string]Client, len(c.Providers)+len(c.Aliases))
+	providers := make(map[string]Client, len(c.Providers))
+	for name, p := range c.Providers {
+		providers[name] = uniai.New(append(p.Options(), client.WithRoutes(routes))...)
+		c.clients[name] = withModel(providers[name], p.Model)
+	}
+	for name, a := range c.Aliases {
+		// 以别名作为默认模型，由路由解析为别名的服务商、模型与默认参数
+		c.clients[name] = withModel(providers[a.Provider], name)
+	}
+}
+
+// Client 返回指定名称的服务商或别名对应的客户端，name 为空时使用默认的服务商或别名。
+// 别名对应的客户端在请求未指定模型时使用别名，服务商对应的客户端使用服务商的默认模型；
+// 请求的模型是别名时，任一客户端都会将请求转发给别名的目标。
+func (c *Config) Client(name string) (Client, error) {
+	if name == "" {
+		name = c.Default
+	}
+	cli, ok := c.clients[name]
+	if !ok {
+		return nil, errors.Wrapf(errorx.NotFound, "config: provider or alias %q", name)
+	}
+	return cli, nil
+}
+
+// Names 返回所有服务商与别名的名称，按字典序排列
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.clients))
+	for name := range c.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func complete(t *testing.T, c Client, model string) response.Message {
+	t.Helper()
+	in := *request.NewRequest(request.WithModel(model), request.WithMessages([]request.Messages{request.NewUserMessage("hi")}))
+	out, err := c.Completions(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := response.NewAccumulator()
+	for item := range out {
+		acc.Add(item)
+	}
+	if err := acc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return acc.Message()
+}
+
+func Test_Parse(t *testing.T) {
+	srv := uniaitest.NewServer(t, uniaitest.FormatOpenAI, uniaitest.Error(errorx.ServerError), uniaitest.Text("ok"), uniaitest.Text("fast"), uniaitest.Text("routed"))
+	t.Setenv("UNIAI_TEST_TOKEN", "sk-test")
+
+	cfg, err := Parse([]byte(`{
+		"default": "main",
+		"providers": {
+			"main": {
+				"type": "OpenAI",
+				"host": "${UNIAI_TEST_HOST:-`+srv.URL+`}",
+				"token": "Bearer ${UNIAI_TEST_TOKEN}",
+				"model": "gpt-4o",
+				"timeout": "5s",
+				"header": {"X-Secret": "pa$$word $HOME ${"},
+				"retry": {"max_attempts": 2, "backoff": 0.001}
+			},
+			"zhipu": {"type": "zhipu", "token": "id.secret"}
+		},
+		"aliases": {"fast": {"provider": "main", "model": "gpt-4o-mini", "temperature": 0.2}}
+	}`), ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := cfg.Providers["main"]; p.Host != srv.URL || time.Duration(p.Timeout) != 5*time.Second || time.Duration(p.Retry.Backoff) != time.Millisecond {
+		t.Fatalf("unexpected provider: %+v", p)
+	}
+	// 只展开 ${…} 形式的引用
+	if got := cfg.Providers["main"].Header["X-Secret"]; got != "pa$$word $HOME ${" {
+		t.Fatalf("header = %q", got)
+	}
+	// 未设置地址时使用服务商的默认地址
+	if host := client.NewOptions(cfg.Providers["zhipu"].Options()...).Host; host != "https://open.bigmodel.cn" {
+		t.Fatalf("default host = %q", host)
+	}
+
+	c, _ := cfg.Client("")
+	if msg := complete(t, c, ""); msg.Content != "ok" {
+		t.Fatalf("content = %q", msg.Content)
+	}
+	srv.AssertRequestCount(t, 2)
+	srv.Request(t, 1).AssertHeader(t, "Authorization", "Bearer sk-test")
+	if in := srv.Request(t, 1).Request(t); in.Model != "gpt-4o" {
+		t.Fatalf("default model = %q", in.Model)
+	}
+
+	c, _ = cfg.Client("fast")
+	complete(t, c, "")
+	if in := srv.Request(t, 2).Request(t); in.Model != "gpt-4o-mini" || in.Temperature == nil || *in.Temperature != 0.2 {
+		t.Fatalf("alias request = %+v", in)
+	}
+
+	// 服务商的客户端同样可以使用别名作为模型名称
+	c, _ = cfg.Client("main")
+	complete(t, c, "fast")
+	if in := srv.Request(t, 3).Request(t); in.Model != "gpt-4o-mini" {
+		t.Fatalf("routed model = %q", in.Model)
+	}
+	if _, err := cfg.Client("slow"); !errors.Is(err, errorx.NotFound) {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+	if names := strings.Join(cfg.Names(), ","); names != "fast,main,zhipu" {
+		t.Fatalf("names = %s", names)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	_, err := Parse([]byte(`{
+		"default": "missing",
+		"providers": {
+			"a": {"type": "unknown", "host": "localhost:8080"},
+			"b": {"token": "${UNIAI_TEST_UNSET}"}
+		},
+		"aliases": {"a": {"provider": "c"}}
+	}`), ".json")
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, errorx.InvalidInput) {
+		t.Fatalf("err = %v, want ValidationError", err)
+	}
+	want := []string{
+		"aliases.a.model: required",
+		"aliases.a.provider: unknown provider",
+		"aliases.a: conflicts",
+		"default: unknown provider or alias",
+		"providers.a.host: invalid url",
+		"providers.a.type: unknown provider type",
+		"providers.b.token: environment variable UNIAI_TEST_UNSET is not set",
+		"providers.b.type: required",
+	}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("problems:\n%s", err)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(verr.Problems[i], prefix) {
+			t.Errorf("problem %d = %q, want prefix %q", i, verr.Problems[i], prefix)
+		}
+	}
+
+	if _, err := Parse([]byte("providers: {}"), ".yaml"); !errors.Is(err, errorx.NotSupported) {
+		t.Fatalf("err = %v, want NotSupported", err)
+	}
+}
+
+func Test_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uniai.json")
+	// 先写入临时文件再重命名，避免读取到写了一半的文件
+	write := func(content string) {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"providers": {"a": {"type": "openai"}}}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan error, 1)
+	w, err := Watch(ctx, path, WithInterval(5*time.Millisecond), WithOnReload(func(cfg *Config, err error) { reloads <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write(`{"providers": {"a": {"type": "openai"}, "b": {"type": "zhipu"}}}`)
+	if err := <-reloads; err != nil || len(w.Config().Providers) != 2 {
+		t.Fatalf("reload: %v", err)
+	}
+
+	// 写错的配置不会替换原配置
+	write(`{"providers": {"a": {}}}`)
+	if err := <-reloads; err == nil || len(w.Config().Providers) != 2 {
+		t.Fatalf("invalid config should be rejected: %v", err)
+	}
+	if _, err := w.Client("b"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// expand 展开 tree 中所有字符串引用的环境变量，未设置且没有默认值的环境变量会以字段路径记录到 verr 中。
+// 键不是字符串的映射（如部分 YAML 库的解析结果）会被转换为以字符串为键的映射。
+func expand(tree any, path string, verr *ValidationError) any {
+	switch v := tree.(type) {
+	case string:
+		return expandString(v, path, verr)
+	case map[string]any:
+		for k, item := range v {
+			v[k] = expand(item, join(path, k), verr)
+		}
+		return v
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			key := fmt.Sprint(k)
+			out[key] = expand(item, join(path, key), verr)
+		}
+		return out
+	case []any:
+		for i, item := range v {
+			v[i] = expand(item, fmt.Sprintf("%s[%d]", path, i), verr)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// expandString 展开 ${NAME} 与 ${NAME:-default} 形式的环境变量引用。
+// 只有 ${…} 形式的引用会被展开，其余的 $ 原样保留，如令牌中的 pa$$word 与 $NAME。
+func expandString(s, path string, verr *ValidationError) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		name, def, hasDefault := strings.Cut(s[start+2:start+end], ":-")
+		b.WriteString(s[:start])
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			b.WriteString(v)
+		} else {
+			if !hasDefault {
+				verr.add(path, "environment variable %s is not set", name)
+			}
+			b.WriteString(def)
+		}
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryTransport 按重试策略重试失败的请求
+type retryTransport struct {
+	rt     http.RoundTripper
+	policy Retry
+}
+
+// newRetryTransport 创建 retryTransport，未设置的策略使用默认值
+func newRetryTransport(rt http.RoundTripper, policy Retry) *retryTransport {
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.Backoff == 0 {
+		policy.Backoff = Duration(500 * time.Millisecond)
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = Duration(10 * time.Second)
+	}
+	return &retryTransport{rt: rt, policy: policy}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.rt.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		// 请求体只能读取一次，无法重新获取请求体的请求不会被重试
+		next := req
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return resp, err
+			}
+			next = req.Clone(ctx)
+			next.Body = body
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		req = next
+	}
+}
+
+// backoff 返回第 attempt 次请求失败后的等待时间，服务商返回了 Retry-After 时优先使用
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	limit := time.Duration(t.policy.MaxBackoff)
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, limit)
+		}
+	}
+
+	wait := time.Duration(t.policy.Backoff)
+	for i := 1; i < attempt && wait < limit; i++ {
+		wait *= 2
+	}
+	return min(wait, limit)
+}
+
+// retryable 返回请求是否可以重试：网络错误、429 与 5xx 响应可以重试
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
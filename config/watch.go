@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Watcher 持有配置文件的最新配置，并在文件变化时重新读取。
+// 新的配置没有通过校验时继续使用原配置，因此写错的配置文件不会影响正在运行的服务。
+type Watcher struct {
+	path string
+	opts *Options
+	cfg  atomic.Pointer[Config]
+	data []byte
+}
+
+// Watch 读取配置文件并在后台定期检查文件内容是否变化，直到 ctx 结束。
+// 首次读取失败时返回错误。
+func Watch(ctx context.Context, path string, opts ...Option) (*Watcher, error) {
+	w := &Watcher{path: path, opts: NewOptions(opts...)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "config: read file")
+	}
+	cfg, err := parse(data, filepath.Ext(path), w.opts)
+	if err != nil {
+		return nil, err
+	}
+	w.cfg.Store(cfg)
+	w.data = data
+
+	go w.watch(ctx)
+	return w, nil
+}
+
+// Config 返回最新的配置
+func (w *Watcher) Config() *Config {
+	return w.cfg.Load()
+}
+
+// Client 返回最新配置中指定名称的服务商或别名对应的客户端
+func (w *Watcher) Client(name string) (Client, error) {
+	return w.Config().Client(name)
+}
+
+// watch 定期检查文件内容，变化时重新读取
+func (w *Watcher) watch(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		data, err := os.ReadFile(w.path)
+		if err != nil || bytes.Equal(data, w.data) {
+			// 编辑器保存文件时可能短暂删除文件，读取失败时等待下次检查
+			continue
+		}
+		w.data = data
+
+		cfg, err := parse(data, filepath.Ext(w.path), w.opts)
+		if err != nil {
+			slog.Error("config reload error", slog.String("path", w.path), slog.Any("err", err))
+		} else {
+			w.cfg.Store(cfg)
+			slog.Info("config reloaded", slog.String("path", w.path))
+		}
+		if w.opts.OnReload != nil {
+			w.opts.OnReload(cfg, err)
+		}
+	}
+}
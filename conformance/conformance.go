@@ -0,0 +1,369 @@
+// Package conformance 提供 client.IClient 实现的一致性检查。
+//
+// 每个适配器都在 uniaitest.Server 模拟的服务商接口上运行同一组检查，覆盖流式与非流式响应、错误状态码、
+// 格式错误的分片、错误事件、空闲超时、被截断的流、提前取消、上下文超时、缺少 [DONE] 结束标记以及用量统计：
+//
+//	func Test_Conformance(t *testing.T) {
+//		conformance.Run(t, conformance.Provider{
+//			Name:    "openai",
+//			Format:  uniaitest.FormatOpenAI,
+//			New:     openai.NewClient,
+//			Options: []client.Option{client.AddHeader("Authorization", "Bearer sk")},
+//		})
+//	}
+package conformance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+// 以下为检查项的名称，同时也是子测试的名称
+const (
+	Stream         = "Stream"         // 流式响应的分片按顺序返回，并携带结束原因
+	NonStream      = "NonStream"      // 非流式响应返回完整的消息
+	Usage          = "Usage"          // 流式与非流式响应都返回用量统计
+	ErrorStatus    = "ErrorStatus"    // 错误状态码转换为可通过 errors.Is 判断的错误类别
+	MalformedChunk = "MalformedChunk" // 格式错误的分片以 errorx.ChunkError 结束响应，或按选项被跳过
+	ErrorEvent     = "ErrorEvent"     // 流中的错误事件以 *errorx.APIError 结束响应
+	IdleTimeout    = "IdleTimeout"    // 超过空闲时间没有收到分片时以 errorx.StreamTimeout 结束响应
+	Truncated      = "Truncated"      // 没有结束原因与 [DONE] 就断开的流以 errorx.StreamTruncated 结束响应
+	Cancel         = "Cancel"         // 调用方读取部分分片后取消上下文且不再读取，连接与协程都被释放
+	Deadline       = "Deadline"       // 上下文超时后请求及时结束
+	MissingDone    = "MissingDone"    // 流式响应缺少 [DONE] 结束标记时已收到的内容不丢失，通道被关闭
+)
+
+// waitTimeout 是等待通道关闭的最长时间，超过该时间视为适配器没有正确结束
+const waitTimeout = 2 * time.Second
+
+// Provider 描述一个待检查的适配器
+type Provider struct {
+	Name    string                // 适配器名称，作为子测试的名称
+	Format  string                // 模拟服务的接口格式，取值为 uniaitest.FormatOpenAI 等
+	New     func() client.IClient // 创建适配器
+	Options []client.Option       // 额外的客户端选项，如鉴权请求头，Host 由检查设置为模拟服务的地址
+	Known   map[string]string     // 已知不满足的检查项及原因，对应的检查会被跳过而不是失败
+}
+
+// check 是一项一致性检查
+type check struct {
+	name string
+	run  func(t *testing.T, p Provider)
+}
+
+// checks 是按顺序执行的全部检查项
+var checks = []check{
+	{Stream, checkStream},
+	{NonStream, checkNonStream},
+	{Usage, checkUsage},
+	{ErrorStatus, checkErrorStatus},
+	{MalformedChunk, checkMalformedChunk},
+	{ErrorEvent, checkErrorEvent},
+	{IdleTimeout, checkIdleTimeout},
+	{Truncated, checkTruncated},
+	{Cancel, checkCancel},
+	{Deadline, checkDeadline},
+	{MissingDone, checkMissingDone},
+}
+
+// Run 对适配器执行全部检查，每项检查作为一个子测试运行。
+// 未能通过的检查可以在 Provider.Known 中登记原因，以便在修复之前保持测试通过。
+func Run(t *testing.T, p Provider) {
+	t.Helper()
+	t.Run(p.Name, func(t *testing.T) {
+		for _, c := range checks {
+			t.Run(c.name, func(t *testing.T) {
+				if reason, ok := p.Known[c.name]; ok {
+					t.Skipf("known failure: %s", reason)
+				}
+				c.run(t, p)
+			})
+		}
+	})
+}
+
+// start 启动模拟服务并返回指向该服务的适配器与选项
+func (p Provider) start(t *testing.T, replies ...uniaitest.Reply) (*uniaitest.Server, client.IClient, client.Options) {
+	return p.startWith(t, nil, replies...)
+}
+
+// startWith 与 start 相同，但额外应用 extra 中的客户端选项。
+// 适配器发出的请求会经过 bodies 统计，检查结束时所有响应体都必须已被关闭。
+func (p Provider) startWith(t *testing.T, extra []client.Option, replies ...uniaitest.Reply) (*uniaitest.Server, client.IClient, client.Options) {
+	srv := uniaitest.NewServer(t, p.Format, replies...)
+	tracker := &bodies{rt: http.DefaultTransport}
+	t.Cleanup(func() { tracker.verify(t) })
+
+	opts := append([]client.Option{client.WithTransport(tracker)}, p.Options...)
+	opts = append(append(opts, extra...), client.WithHost(srv.URL))
+	return srv, p.New(), *client.NewOptions(opts...)
+}
+
+// bodies 是统计尚未关闭的响应体的 http.RoundTripper
+type bodies struct {
+	rt   http.RoundTripper
+	open atomic.Int64
+}
+
+func (b *bodies) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := b.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b.open.Add(1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, b: b}
+	return resp, nil
+}
+
+// verify 等待所有响应体被关闭，超过 waitTimeout 时检查失败
+func (b *bodies) verify(t *testing.T) {
+	deadline := time.Now().Add(waitTimeout)
+	for b.open.Load() > 0 {
+		if time.Now().After(deadline) {
+			t.Errorf("%d response bodies not closed", b.open.Load())
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// trackedBody 在第一次关闭时更新统计
+type trackedBody struct {
+	io.ReadCloser
+	b    *bodies
+	once sync.Once
+}
+
+func (t *trackedBody) Close() error {
+	t.once.Do(func() { t.b.open.Add(-1) })
+	return t.ReadCloser.Close()
+}
+
+// newRequest 创建检查使用的补全请求
+func newRequest(stream bool) request.Request {
+	return *request.NewRequest(
+		request.WithModel("conformance-model"),
+		request.WithStream(stream),
+		request.WithMessages([]request.Messages{request.NewUserMessage("hi")}),
+	)
+}
+
+// collect 读取通道中的全部分片，通道在 waitTimeout 内没有关闭时检查失败
+func collect(t *testing.T, out chan response.Response) *response.Accumulator {
+	t.Helper()
+	acc := response.NewAccumulator()
+	timer := time.NewTimer(waitTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case item, ok := <-out:
+			if !ok {
+				return acc
+			}
+			acc.Add(item)
+		case <-timer.C:
+			t.Fatalf("channel not closed within %s", waitTimeout)
+		}
+	}
+}
+
+// complete 发送请求并读取全部分片
+func complete(t *testing.T, p Provider, stream bool, replies ...uniaitest.Reply) *response.Accumulator {
+	t.Helper()
+	return completeWith(t, p, nil, stream, replies...)
+}
+
+// completeWith 与 complete 相同，但额外应用 extra 中的客户端选项
+func completeWith(t *testing.T, p Provider, extra []client.Option, stream bool, replies ...uniaitest.Reply) *response.Accumulator {
+	t.Helper()
+	_, c, opts := p.startWith(t, extra, replies...)
+	out, err := c.Completions(opts, context.Background(), newRequest(stream))
+	if err != nil {
+		t.Fatalf("Completions: %v", err)
+	}
+	if out == nil {
+		t.Fatal("Completions returned a nil channel without error")
+	}
+	return collect(t, out)
+}
+
+func checkStream(t *testing.T, p Provider) {
+	acc := complete(t, p, true, uniaitest.Text("你好", "，", "世界"))
+	if err := acc.Err(); err != nil {
+		t.Errorf("unexpected stream error: %v", err)
+	}
+	if got := acc.Message().Content; got != "你好，世界" {
+		t.Errorf("content = %q, want %q", got, "你好，世界")
+	}
+	if got := acc.FinishReason(); got != "stop" {
+		t.Errorf("finish reason = %q, want stop", got)
+	}
+}
+
+func checkNonStream(t *testing.T, p Provider) {
+	acc := complete(t, p, false, uniaitest.Text("你好", "世界"))
+	if got := acc.Message().Content; got != "你好世界" {
+		t.Errorf("content = %q, want %q", got, "你好世界")
+	}
+	if got := acc.FinishReason(); got != "stop" {
+		t.Errorf("finish reason = %q, want stop", got)
+	}
+}
+
+func checkUsage(t *testing.T, p Provider) {
+	want := response.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}
+	for _, stream := range []bool{true, false} {
+		acc := complete(t, p, stream, uniaitest.Reply{Content: []string{"o", "k"}, Usage: &want})
+		if got := acc.Usage(); got == nil || got.PromptTokens != want.PromptTokens || got.CompletionTokens != want.CompletionTokens || got.TotalTokens != want.TotalTokens {
+			t.Errorf("stream=%v: usage = %+v, want %+v", stream, got, want)
+		}
+	}
+}
+
+func checkErrorStatus(t *testing.T, p Provider) {
+	for _, tt := range []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, errorx.InvalidRequest},
+		{http.StatusUnauthorized, errorx.Unauthorized},
+		{http.StatusTooManyRequests, errorx.RateLimited},
+		{http.StatusInternalServerError, errorx.ServerError},
+	} {
+		for _, stream := range []bool{true, false} {
+			_, c, opts := p.start(t, uniaitest.Error(&errorx.APIError{StatusCode: tt.status, Message: "conformance"}))
+			out, err := c.Completions(opts, context.Background(), newRequest(stream))
+			if err == nil {
+				t.Errorf("status %d stream=%v: expected error", tt.status, stream)
+				if out != nil {
+					collect(t, out)
+				}
+				continue
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("status %d stream=%v: err = %v, want %v", tt.status, stream, err, tt.want)
+			}
+		}
+	}
+}
+
+func checkMalformedChunk(t *testing.T, p Provider) {
+	reply := uniaitest.Reply{Content: []string{"ok"}, Raw: []string{"{\"choices\":["}}
+
+	acc := complete(t, p, true, reply)
+	var chunkErr *errorx.ChunkError
+	if err := acc.Err(); !errors.As(err, &chunkErr) || !errors.Is(err, errorx.InvalidOutput) {
+		t.Errorf("err = %v, want *errorx.ChunkError", err)
+	}
+
+	acc = completeWith(t, p, []client.Option{client.WithSkipMalformedChunks()}, true, reply)
+	if err := acc.Err(); err != nil || acc.Message().Content != "ok" {
+		t.Errorf("skip policy: content = %q, err = %v", acc.Message().Content, err)
+	}
+}
+
+func checkErrorEvent(t *testing.T, p Provider) {
+	acc := complete(t, p, true, uniaitest.Reply{Content: []string{"部分"}, StreamErr: &errorx.APIError{Code: "10013", Message: "conformance"}})
+	var apiErr *errorx.APIError
+	if err := acc.Err(); !errors.As(err, &apiErr) || apiErr.Message != "conformance" {
+		t.Errorf("err = %v, want *errorx.APIError", err)
+	}
+	if got := acc.Message().Content; got != "部分" {
+		t.Errorf("content before error = %q", got)
+	}
+}
+
+func checkIdleTimeout(t *testing.T, p Provider) {
+	const delay = 500 * time.Millisecond
+	start := time.Now()
+	acc := completeWith(t, p, []client.Option{client.WithStreamIdleTimeout(50 * time.Millisecond)}, true,
+		uniaitest.Reply{Content: []string{"a", "b"}, Delay: delay})
+	if err := acc.Err(); !errors.Is(err, errorx.StreamTimeout) {
+		t.Errorf("err = %v, want StreamTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("stream took %s, idle timeout not honored", elapsed)
+	}
+}
+
+func checkTruncated(t *testing.T, p Provider) {
+	acc := complete(t, p, true, uniaitest.Reply{Content: []string{"a", "b"}, Truncate: true})
+	if err := acc.Err(); !errors.Is(err, errorx.StreamTruncated) {
+		t.Errorf("err = %v, want StreamTruncated", err)
+	}
+	if got := acc.Message().Content; got != "ab" {
+		t.Errorf("content = %q, want %q", got, "ab")
+	}
+}
+
+func checkCancel(t *testing.T, p Provider) {
+	uniaitest.VerifyNoLeaks(t)
+	_, c, opts := p.start(t, uniaitest.Reply{Content: strings.Split(strings.Repeat("x", 50), ""), Delay: 10 * time.Millisecond})
+
+	// 使用无缓冲的通道，使输出协程在每次发送时都等待调用方读取
+	in := newRequest(true)
+	in.ChannelMaxLength = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, err := c.Completions(opts, ctx, in)
+	if err != nil {
+		cancel()
+		t.Fatalf("Completions: %v", err)
+	}
+
+	select {
+	case <-out:
+	case <-time.After(waitTimeout):
+		t.Fatal("no chunk received")
+	}
+
+	// 取消后不再读取通道，连接与输出协程也必须被释放，由清理函数检查
+	cancel()
+}
+
+func checkDeadline(t *testing.T, p Provider) {
+	const delay = 500 * time.Millisecond
+	_, c, opts := p.start(t, uniaitest.Reply{Content: []string{"a", "b"}, Delay: delay})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	out, err := c.Completions(opts, ctx, newRequest(true))
+	if err == nil {
+		collect(t, out)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("request took %s, deadline not honored", elapsed)
+	}
+
+	// 响应头到达之前超时的请求返回 ctx 的错误
+	_, c, opts = p.start(t, uniaitest.Reply{Content: []string{"a"}, Delay: delay})
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Completions(opts, ctx, newRequest(false)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func checkMissingDone(t *testing.T, p Provider) {
+	acc := complete(t, p, true, uniaitest.Reply{Content: []string{"a", "b"}, OmitDone: true})
+	if err := acc.Err(); err != nil {
+		t.Errorf("stream with finish reason but without [DONE] should succeed: %v", err)
+	}
+	if got := acc.Message().Content; got != "ab" {
+		t.Errorf("content = %q, want %q", got, "ab")
+	}
+}
@@ -0,0 +1,30 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/conformance"
+	"github.com/jun3372/uniai/internal/ark"
+	"github.com/jun3372/uniai/internal/hunyuan"
+	"github.com/jun3372/uniai/internal/openai"
+	"github.com/jun3372/uniai/internal/tongyi"
+	"github.com/jun3372/uniai/internal/xfyun"
+	"github.com/jun3372/uniai/internal/zhipu"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+// 千帆尚未支持补全，暂不在一致性检查之内。
+func Test_Conformance(t *testing.T) {
+	bearer := []client.Option{client.AddHeader("Authorization", "Bearer sk-conformance")}
+	for _, p := range []conformance.Provider{
+		{Name: client.OpenAI, Format: uniaitest.FormatOpenAI, New: openai.NewClient, Options: bearer},
+		{Name: client.Xfyun, Format: uniaitest.FormatSpark, New: xfyun.NewClient, Options: bearer},
+		{Name: client.Tongyi, Format: uniaitest.FormatOpenAI, New: tongyi.NewClient, Options: bearer},
+		{Name: client.Zhipu, Format: uniaitest.FormatOpenAI, New: zhipu.NewClient, Options: []client.Option{client.AddHeader("Authorization", "id.secret")}},
+		{Name: client.Ark, Format: uniaitest.FormatOpenAI, New: ark.NewClient, Options: bearer},
+		{Name: client.Hunyuan, Format: uniaitest.FormatTC3, New: hunyuan.NewClient, Options: []client.Option{client.WithSecret("AKIDconformance", "secret")}},
+	} {
+		conformance.Run(t, p)
+	}
+}
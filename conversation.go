@@ -0,0 +1,368 @@
+package uniai
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/tokenizer"
+)
+
+// ConversationOptions 结构体定义了会话的选项
+type ConversationOptions struct {
+	SystemPrompt   string              // 系统提示词，始终位于消息列表的最前面，不会被裁剪
+	TokenBudget    int                 // 提示词的 Token 上限，0 表示不裁剪
+	Trimmer        Trimmer             // 超出上限时使用的裁剪策略，默认为 SlidingWindow
+	Tokenizer      tokenizer.Tokenizer // 计算 Token 数量使用的分词器，默认根据模型选择
+	RequestOptions []request.Option    // 每次请求使用的选项，如模型、温度与是否流式输出
+	Store          ConversationStore   // 会话的持久化存储，为 nil 时不持久化
+	ID             string              // 会话在存储中的标识
+}
+
+// ConversationOption 是一个函数类型，用于修改 ConversationOptions 结构体
+type ConversationOption func(*ConversationOptions)
+
+// WithSystemPrompt 设置会话的系统提示词
+func WithSystemPrompt(prompt string) ConversationOption {
+	return func(o *ConversationOptions) { o.SystemPrompt = prompt }
+}
+
+// WithTokenBudget 设置提示词的 Token 上限
+func WithTokenBudget(budget int) ConversationOption {
+	return func(o *ConversationOptions) { o.TokenBudget = budget }
+}
+
+// WithTrimmer 设置超出上限时使用的裁剪策略
+func WithTrimmer(t Trimmer) ConversationOption {
+	return func(o *ConversationOptions) { o.Trimmer = t }
+}
+
+// WithTokenizer 设置计算 Token 数量使用的分词器
+func WithTokenizer(t tokenizer.Tokenizer) ConversationOption {
+	return func(o *ConversationOptions) { o.Tokenizer = t }
+}
+
+// WithRequestOptions 设置每次请求使用的选项
+func WithRequestOptions(opts ...request.Option) ConversationOption {
+	return func(o *ConversationOptions) { o.RequestOptions = append(o.RequestOptions, opts...) }
+}
+
+// Conversation 是一个多轮会话，它保存系统提示词与历史消息，
+// 每次发送时自动携带历史消息，收到回复后自动追加到历史中，并在超出 Token 上限时按策略裁剪。
+// Conversation 可以被多个协程使用，但同一时刻只应有一个进行中的请求。
+type Conversation struct {
+	mu       sync.Mutex
+	c        Completer
+	opts     ConversationOptions
+	messages []request.Messages // 历史消息，不包含系统提示词
+}
+
+// NewConversation 创建一个基于指定客户端的会话
+func NewConversation(c Completer, opts ...ConversationOption) *Conversation {
+	o := ConversationOptions{Trimmer: SlidingWindow()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Conversation{c: c, opts: o}
+}
+
+// SetSystemPrompt 修改会话的系统提示词
+func (c *Conversation) SetSystemPrompt(prompt string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.opts.SystemPrompt = prompt
+}
+
+// Messages 返回历史消息的副本，不包含系统提示词
+func (c *Conversation) Messages() []request.Messages {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]request.Messages(nil), c.messages...)
+}
+
+// Append 向历史中追加消息，如工具调用的结果。设置了存储时消息会同时追加到存储中，返回存储的错误。
+func (c *Conversation) Append(ctx context.Context, messages ...request.Messages) error {
+	c.add(messages...)
+
+	now := time.Now()
+	turns := make([]Turn, 0, len(messages))
+	for _, msg := range messages {
+		turns = append(turns, Turn{Message: msg, CreatedAt: now})
+	}
+	return c.persist(ctx, turns...)
+}
+
+// add 向内存中的历史追加消息
+func (c *Conversation) add(messages ...request.Messages) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, messages...)
+}
+
+// Reset 清空历史消息，系统提示词保持不变
+func (c *Conversation) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = nil
+}
+
+// Send 发送一条用户消息并返回回复的通道。
+// 通道中的分片会被归并为完整的助手消息，并在通道关闭前追加到历史中。
+// 请求失败、回复以错误结束或 ctx 被取消时，用户消息与不完整的回复都不会保留在历史中，
+// 为本次请求裁剪掉的历史也会被恢复。
+func (c *Conversation) Send(ctx context.Context, content string) (chan response.Response, error) {
+	user := Turn{Message: request.NewUserMessage(content), CreatedAt: time.Now()}
+	in, prev, err := c.prepare(ctx, user.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.c.Completions(ctx, in)
+	if err != nil {
+		c.rollback(prev)
+		return nil, err
+	}
+
+	forward := make(chan response.Response, cap(out))
+	go func() {
+		defer close(forward)
+
+		acc := response.NewAccumulator()
+		for item := range out {
+			acc.Add(item)
+			select {
+			case forward <- item:
+			case <-ctx.Done():
+				// 调用方已放弃读取，不完整的回复不会追加到历史中。
+				c.rollback(prev)
+				return
+			}
+		}
+
+		// 出错或被取消的回复是不完整的，不会追加到历史中。
+		msg := acc.Message()
+		if acc.Err() != nil || ctx.Err() != nil || (msg.Content == "" && len(msg.ToolCalls) == 0) {
+			c.rollback(prev)
+			return
+		}
+		reply := Turn{Message: assistantMessage(msg), Provider: provider(c.c), Model: acc.Response().Model, Usage: acc.Usage(), CreatedAt: time.Now()}
+		if reply.Model == "" {
+			reply.Model = in.Model
+		}
+
+		c.add(reply.Message)
+		if err := c.persist(context.WithoutCancel(ctx), user, reply); err != nil {
+			slog.Error("conversation persist error", slog.String("id", c.opts.ID), slog.Any("err", err))
+		}
+	}()
+	return forward, nil
+}
+
+// Ask 发送一条用户消息并等待完整的回复
+func (c *Conversation) Ask(ctx context.Context, content string) (response.Message, error) {
+	out, err := c.Send(ctx, content)
+	if err != nil {
+		return response.Message{}, err
+	}
+
+	acc := response.NewAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			return response.Message{}, ctx.Err()
+		case item, ok := <-out:
+			if !ok {
+				return acc.Message(), acc.Err()
+			}
+			acc.Add(item)
+		}
+	}
+}
+
+// prepare 追加用户消息、按上限裁剪历史，并构建本次请求。
+// 返回追加前的历史，请求失败时通过 rollback 恢复，裁剪后的历史只有在请求成功时才会保留。
+func (c *Conversation) prepare(ctx context.Context, msg request.Messages) (request.Request, []request.Messages, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	in := newRequest(c.opts.RequestOptions)
+	prev := c.messages[:len(c.messages):len(c.messages)]
+	c.messages = append(prev, msg)
+
+	if c.opts.TokenBudget > 0 && c.opts.Trimmer != nil {
+		t := c.opts.Tokenizer
+		if t == nil {
+			t = tokenizer.ForModel(in.Model)
+		}
+		fits := func(history []request.Messages) bool {
+			return tokenizer.CountMessages(t, c.withSystem(history)) <= c.opts.TokenBudget
+		}
+
+		if !fits(c.messages) {
+			trimmed, err := c.opts.Trimmer.Trim(ctx, c.messages, fits)
+			if err != nil {
+				c.messages = prev
+				return in, nil, err
+			}
+			c.messages = trimmed
+		}
+	}
+
+	in.Messages = c.withSystem(c.messages)
+	return in, prev, nil
+}
+
+// rollback 将历史恢复为 prepare 返回的发送前的历史，用于请求失败时
+func (c *Conversation) rollback(prev []request.Messages) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = prev
+}
+
+// withSystem 返回带有系统提示词的完整消息列表
+func (c *Conversation) withSystem(history []request.Messages) []request.Messages {
+	if c.opts.SystemPrompt == "" {
+		return append([]request.Messages(nil), history...)
+	}
+	return append([]request.Messages{request.NewSystemMessage(c.opts.SystemPrompt)}, history...)
+}
+
+// provider 返回客户端的服务商类型，无法获取时返回空字符串
+func provider(c Completer) string {
+	if p, ok := c.(interface{ Provider() string }); ok {
+		return p.Provider()
+	}
+	return ""
+}
+
+// assistantMessage 将回复转换为可以放入历史的助手消息，思考过程不会被保留
+func assistantMessage(msg response.Message) request.Messages {
+	out := request.NewAssistantMessage(msg.Content)
+	for _, call := range msg.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, request.ToolCall{
+			ID:       call.ID,
+			Type:     call.Type,
+			Function: request.FunctionCall{Name: call.Function.Name, Arguments: call.Function.Arguments},
+		})
+	}
+	return out
+}
+
+// Trimmer 接口定义了历史消息的裁剪策略
+type Trimmer interface {
+	// Trim 裁剪历史消息直到 fits 返回 true，history 不包含系统提示词，最后一轮对话应始终保留。
+	Trim(ctx context.Context, history []request.Messages, fits func([]request.Messages) bool) ([]request.Messages, error)
+}
+
+// TrimmerFunc 是一个函数类型，实现了 Trimmer 接口
+type TrimmerFunc func(ctx context.Context, history []request.Messages, fits func([]request.Messages) bool) ([]request.Messages, error)
+
+// Trim 调用函数本身
+func (f TrimmerFunc) Trim(ctx context.Context, history []request.Messages, fits func([]request.Messages) bool) ([]request.Messages, error) {
+	return f(ctx, history, fits)
+}
+
+// SlidingWindow 返回滑动窗口裁剪策略，从最早的一轮对话开始丢弃，直到满足上限或只剩最后一轮。
+func SlidingWindow() Trimmer {
+	return KeepFirst(0)
+}
+
+// KeepFirst 返回保留前 n 轮对话的裁剪策略，适用于开头包含任务背景的会话。
+// 它会保留前 n 轮，从第 n+1 轮开始丢弃，直到满足上限或只剩前 n 轮与最后一轮。
+func KeepFirst(n int) Trimmer {
+	return TrimmerFunc(func(_ context.Context, history []request.Messages, fits func([]request.Messages) bool) ([]request.Messages, error) {
+		groups := turns(history)
+		for len(groups) > n+1 && !fits(flatten(groups)) {
+			groups = append(groups[:n], groups[n+1:]...)
+		}
+		return flatten(groups), nil
+	})
+}
+
+// Summarize 返回摘要裁剪策略，超出上限时调用模型将较早的对话总结为一条摘要消息，保留最近的对话。
+// 摘要消息以系统消息的形式放在历史的最前面，下次裁剪时会与其他较早的对话一起重新总结。
+func Summarize(c Completer, opts ...request.Option) Trimmer {
+	return TrimmerFunc(func(ctx context.Context, history []request.Messages, fits func([]request.Messages) bool) ([]request.Messages, error) {
+		groups := turns(history)
+
+		// 从最早的对话开始移出，直到剩余的对话加上摘要占位可以满足上限。
+		split := 0
+		placeholder := []request.Messages{request.NewSystemMessage(summaryPrefix)}
+		for split < len(groups)-1 && !fits(append(placeholder, flatten(groups[split:])...)) {
+			split++
+		}
+		if split == 0 {
+			return history, nil
+		}
+
+		summary, err := summarize(ctx, c, flatten(groups[:split]), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		out := append([]request.Messages{request.NewSystemMessage(summaryPrefix + summary)}, flatten(groups[split:])...)
+		// 摘要本身过长时退回滑动窗口，但保留摘要。
+		return KeepFirst(1).Trim(ctx, out, fits)
+	})
+}
+
+// summaryPrefix 是摘要消息的前缀
+const summaryPrefix = "以下是之前对话的摘要：\n"
+
+// summarize 调用模型总结对话
+func summarize(ctx context.Context, c Completer, history []request.Messages, opts []request.Option) (string, error) {
+	var transcript strings.Builder
+	for _, msg := range history {
+		transcript.WriteString(msg.Role)
+		transcript.WriteString(": ")
+		transcript.WriteString(strings.TrimPrefix(msg.Content, summaryPrefix))
+		transcript.WriteString("\n")
+	}
+
+	in := newRequest(opts)
+	in.Stream = false
+	in.Messages = []request.Messages{
+		request.NewSystemMessage("请简洁地总结下面的对话，保留人物、事实、结论与未完成的事项，只输出摘要。"),
+		request.NewUserMessage(transcript.String()),
+	}
+
+	out, err := c.Completions(ctx, in)
+	if err != nil {
+		return "", err
+	}
+	return collect(ctx, out)
+}
+
+// newRequest 使用选项创建请求
+func newRequest(opts []request.Option) request.Request {
+	in := *request.NewRequest()
+	for _, opt := range opts {
+		opt(&in)
+	}
+	return in
+}
+
+// turns 将历史消息按轮次分组，每轮以用户消息开始，开头的非用户消息（如摘要）单独成为一组
+func turns(history []request.Messages) [][]request.Messages {
+	var groups [][]request.Messages
+	for i, msg := range history {
+		if i == 0 || msg.Role == request.MessageRoleUser {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], msg)
+	}
+	return groups
+}
+
+// flatten 将分组的消息展开为消息列表
+func flatten(groups [][]request.Messages) []request.Messages {
+	var out []request.Messages
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
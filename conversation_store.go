@@ -0,0 +1,85 @@
+package uniai
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Turn 是会话中持久化的一条消息，除消息本身外还记录了产生它的服务商、模型与用量统计
+type Turn struct {
+	Message   request.Messages `json:"message"`            // 消息内容，包含工具调用
+	Provider  string           `json:"provider,omitempty"` // 产生该消息的服务商，仅助手消息记录
+	Model     string           `json:"model,omitempty"`    // 产生该消息的模型，仅助手消息记录
+	Usage     *response.Usage  `json:"usage,omitempty"`    // 本次回复的用量统计，仅助手消息记录
+	CreatedAt time.Time        `json:"created_at"`         // 消息的创建时间
+}
+
+// ConversationRecord 是持久化的会话
+type ConversationRecord struct {
+	ID        string    `json:"id"`         // 会话标识
+	Turns     []Turn    `json:"turns"`      // 按时间顺序排列的消息
+	CreatedAt time.Time `json:"created_at"` // 会话的创建时间
+	UpdatedAt time.Time `json:"updated_at"` // 会话最后一次追加消息的时间
+}
+
+// ConversationInfo 是会话的摘要信息，用于列出会话
+type ConversationInfo struct {
+	ID        string    `json:"id"`         // 会话标识
+	Turns     int       `json:"turns"`      // 消息数量
+	UpdatedAt time.Time `json:"updated_at"` // 会话最后一次追加消息的时间
+}
+
+// ConversationStore 接口定义了会话的持久化存储，实现需要保证并发安全。
+// 内置的内存与文件存储位于 store 包中。
+type ConversationStore interface {
+	// Load 读取会话，会话不存在或已过期时返回 errorx.NotFound
+	Load(ctx context.Context, id string) (*ConversationRecord, error)
+	// Append 向会话追加消息，会话不存在时自动创建
+	Append(ctx context.Context, id string, turns ...Turn) error
+	// List 列出所有未过期的会话，按最后更新时间倒序排列
+	List(ctx context.Context) ([]ConversationInfo, error)
+	// Delete 删除会话，会话不存在时不返回错误
+	Delete(ctx context.Context, id string) error
+}
+
+// WithStore 设置会话的持久化存储与会话标识，每轮对话完成后用户消息与助手回复会被追加到存储中，
+// 通过 Append 追加的消息（如工具调用的结果）也会立即追加到存储中。
+// 存储中保存的是完整的消息记录，裁剪只影响内存中发送给模型的历史。
+func WithStore(store ConversationStore, id string) ConversationOption {
+	return func(o *ConversationOptions) {
+		o.Store = store
+		o.ID = id
+	}
+}
+
+// LoadConversation 从存储中恢复会话，会话不存在时返回一个空会话。
+func LoadConversation(ctx context.Context, c Completer, store ConversationStore, id string, opts ...ConversationOption) (*Conversation, error) {
+	conv := NewConversation(c, append(opts, WithStore(store, id))...)
+
+	record, err := store.Load(ctx, id)
+	if err != nil {
+		if errors.Is(err, errorx.NotFound) {
+			return conv, nil
+		}
+		return nil, err
+	}
+
+	for _, turn := range record.Turns {
+		conv.messages = append(conv.messages, turn.Message)
+	}
+	return conv, nil
+}
+
+// persist 将一轮对话追加到存储中
+func (c *Conversation) persist(ctx context.Context, turns ...Turn) error {
+	if c.opts.Store == nil {
+		return nil
+	}
+	return c.opts.Store.Append(ctx, c.opts.ID, turns...)
+}
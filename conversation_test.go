@@ -0,0 +1,135 @@
+package uniai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/tokenizer"
+)
+
+// echo 以流式分片的形式回复最后一条消息的内容
+type echo struct {
+	requests []request.Request
+}
+
+func (e *echo) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	e.requests = append(e.requests, in)
+	last := in.Messages[len(in.Messages)-1].Content
+	if in.Messages[0].Content == "请简洁地总结下面的对话，保留人物、事实、结论与未完成的事项，只输出摘要。" {
+		last = "summary"
+	}
+
+	out := make(chan response.Response, 2)
+	out <- response.Response{Choices: []response.Choices{{Delta: &response.Delta{Role: "assistant", Content: "re:"}}}}
+	out <- response.Response{Choices: []response.Choices{{Delta: &response.Delta{Content: last}, FinishReason: "stop"}}}
+	close(out)
+	return out, nil
+}
+
+// words 按空格计数的分词器，便于构造测试用例
+type words struct{}
+
+func (words) Count(text string) int { return len(strings.Fields(text)) }
+
+func Test_Conversation(t *testing.T) {
+	e := &echo{}
+	conv := NewConversation(e, WithSystemPrompt("be brief"), WithRequestOptions(request.WithStream(true)))
+
+	for _, q := range []string{"one", "two"} {
+		msg, err := conv.Ask(context.Background(), q)
+		if err != nil || msg.Content != "re:"+q {
+			t.Fatalf("Ask(%s) = %+v, %v", q, msg, err)
+		}
+	}
+
+	if got := e.requests[1].Messages; len(got) != 4 || got[0].Role != request.MessageRoleSystem || got[2].Content != "re:one" {
+		t.Fatalf("unexpected messages: %+v", got)
+	}
+	if n := len(conv.Messages()); n != 4 {
+		t.Fatalf("history has %d messages, want 4", n)
+	}
+}
+
+// broken 输出一个分片后以错误结束
+type broken struct{}
+
+func (broken) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	out := make(chan response.Response, 2)
+	out <- response.Response{Choices: []response.Choices{{Delta: &response.Delta{Role: "assistant", Content: "partial"}}}}
+	out <- response.Response{Err: errorx.StreamTruncated}
+	close(out)
+	return out, nil
+}
+
+func Test_ConversationRollback(t *testing.T) {
+	conv := NewConversation(broken{})
+	if _, err := conv.Ask(context.Background(), "hi"); !errors.Is(err, errorx.StreamTruncated) {
+		t.Fatalf("err = %v, want StreamTruncated", err)
+	}
+	if n := len(conv.Messages()); n != 0 {
+		t.Fatalf("failed stream left %d messages in history", n)
+	}
+
+	// 调用方取消后放弃读取，用户消息同样不会保留
+	ctx, cancel := context.WithCancel(context.Background())
+	conv = NewConversation(&echo{})
+	out, err := conv.Send(ctx, "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for range out {
+	}
+	if n := len(conv.Messages()); n != 0 {
+		t.Fatalf("cancelled stream left %d messages in history", n)
+	}
+
+	// 为失败的请求裁剪掉的历史会被恢复
+	history := []request.Messages{request.NewUserMessage("a a a"), request.NewAssistantMessage("b b b"), request.NewUserMessage("c"), request.NewAssistantMessage("d")}
+	conv = NewConversation(broken{}, WithTokenBudget(20), WithTrimmer(SlidingWindow()), WithTokenizer(words{}))
+	_ = conv.Append(context.Background(), history...)
+	if _, err := conv.Ask(context.Background(), "e"); err == nil {
+		t.Fatal("broken stream should fail")
+	}
+	if got := conv.Messages(); len(got) != 4 || got[0].Content != "a a a" {
+		t.Fatalf("trimmed history should be restored: %+v", got)
+	}
+}
+
+func Test_Trimmers(t *testing.T) {
+	history := []request.Messages{
+		request.NewUserMessage("a a a"), request.NewAssistantMessage("b b b"),
+		request.NewUserMessage("c c c"), request.NewAssistantMessage("d d d"),
+		request.NewUserMessage("e e e"), request.NewAssistantMessage("f f f"),
+		request.NewUserMessage("g"),
+	}
+	// 每条消息的开销为 3 + 角色 1，回复起始为 3。
+	fits := func(budget int) func([]request.Messages) bool {
+		return func(m []request.Messages) bool { return tokenizer.CountMessages(words{}, m) <= budget }
+	}
+
+	got, _ := SlidingWindow().Trim(context.Background(), history, fits(3+7+7+5))
+	if len(got) != 3 || got[0].Content != "e e e" {
+		t.Fatalf("SlidingWindow = %+v", got)
+	}
+
+	got, _ = KeepFirst(1).Trim(context.Background(), history, fits(3+7+7+5))
+	if len(got) != 3 || got[0].Content != "a a a" || got[2].Content != "g" {
+		t.Fatalf("KeepFirst = %+v", got)
+	}
+
+	e := &echo{}
+	got, err := Summarize(e).Trim(context.Background(), history, fits(3+6+7+7+5))
+	if err != nil || len(got) != 4 || got[0].Content != summaryPrefix+"re:summary" || got[1].Content != "e e e" {
+		t.Fatalf("Summarize = %+v, %v", got, err)
+	}
+	if !strings.Contains(e.requests[0].Messages[1].Content, "assistant: d d d") {
+		t.Fatalf("unexpected transcript: %q", e.requests[0].Messages[1].Content)
+	}
+}
@@ -0,0 +1,67 @@
+package uniai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_EmbeddingsBatching(t *testing.T) {
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in request.EmbeddingRequest
+		if err := sonic.ConfigDefault.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Error(err)
+			return
+		}
+		batches = append(batches, len(in.Input))
+
+		data := make([]string, 0, len(in.Input))
+		for i, text := range in.Input {
+			raw := make([]byte, 4)
+			binary.LittleEndian.PutUint32(raw, math.Float32bits(float32(len(text))))
+			data = append(data, fmt.Sprintf(`{"object":"embedding","index":%d,"embedding":"%s"}`, i, base64.StdEncoding.EncodeToString(raw)))
+		}
+		fmt.Fprintf(w, `{"object":"list","model":"%s","data":[%s],"usage":{"prompt_tokens":%d,"total_tokens":%d}}`,
+			in.Model, strings.Join(data, ","), len(in.Input), len(in.Input))
+	}))
+	defer srv.Close()
+
+	input := make([]string, 23)
+	for i := range input {
+		input[i] = strings.Repeat("a", i+1)
+	}
+
+	ai := New(client.WithType(client.OpenAI), client.WithHost(srv.URL))
+	resp, err := ai.Embeddings(context.Background(), *request.NewEmbeddingRequest(
+		request.WithEmbeddingModel("text-embedding-v3"),
+		request.WithInput(input...),
+		request.WithEncodingFormat(request.EncodingFormatBase64),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(batches) != "[10 10 3]" {
+		t.Fatalf("batches = %v, want [10 10 3]", batches)
+	}
+	if len(resp.Data) != 23 || resp.Usage.TotalTokens != 23 {
+		t.Fatalf("unexpected response: %d items, usage %+v", len(resp.Data), resp.Usage)
+	}
+	for i, item := range resp.Data {
+		if item.Index != i || item.Embedding[0] != float32(i+1) {
+			t.Fatalf("data[%d] = %+v", i, item)
+		}
+	}
+}
@@ -1,10 +1,164 @@
 package errorx
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+)
 
 var (
 	InvalidHost    = errors.New("invalid host")
 	InvalidInput   = errors.New("invalid input")
 	InvalidRequest = errors.New("Invalid Request Error")
 	NotFound       = errors.New("not found")
+
+	Unauthorized     = errors.New("unauthorized")
+	PermissionDenied = errors.New("permission denied")
+	RateLimited      = errors.New("rate limited")
+	QuotaExceeded    = errors.New("quota exceeded")
+	ContentFiltered  = errors.New("content filtered")
+	ServerError      = errors.New("server error")
+
+	InvalidOutput = errors.New("invalid output")
+	NotSupported  = errors.New("not supported")
+
+	StreamTimeout   = errors.New("stream idle timeout")
+	StreamTruncated = errors.New("stream truncated")
 )
+
+// APIError 表示服务商返回的错误信息。
+// 它保留了 HTTP 状态码、服务商错误码以及原始响应体，便于调用方根据错误码做进一步处理。
+type APIError struct {
+	Provider   string // 服务商类型，如 openai、zhipu
+	StatusCode int    // HTTP 状态码
+	Code       string // 服务商返回的错误码
+	Type       string // 服务商返回的错误类型
+	Message    string // 服务商返回的错误描述
+	RequestID  string // 服务商返回的请求标识
+	Body       string // 原始响应体
+	Err        error  // 错误类别，如 Unauthorized、RateLimited，可通过 errors.Is 判断
+}
+
+// Error 实现 error 接口，返回可读的错误描述。
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Provider)
+	b.WriteString(" api error")
+	if e.StatusCode > 0 {
+		fmt.Fprintf(&b, ": status=%d", e.StatusCode)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code=%s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ", message=%s", e.Message)
+	} else if e.Body != "" {
+		fmt.Fprintf(&b, ", body=%s", e.Body)
+	}
+	return b.String()
+}
+
+// Unwrap 返回错误类别，使 errors.Is(err, errorx.RateLimited) 等判断生效。
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// NewAPIError 根据服务商的响应体创建一个 APIError。
+// 它会尝试解析常见的错误结构，如 {"error":{"code":"","message":""}}、{"code":"","message":""} 与 {"error_code":0,"error_msg":""}，
+// 无法解析时仅保留原始响应体。
+func NewAPIError(provider string, statusCode int, body []byte) *APIError {
+	e := &APIError{Provider: provider, StatusCode: statusCode, Body: string(body), Err: StatusError(statusCode)}
+
+	var payload struct {
+		Error     *errorBody `json:"error"`
+		Code      any        `json:"code"`
+		Message   string     `json:"message"`
+		RequestID string     `json:"request_id"`
+		ErrorCode any        `json:"error_code"`
+		ErrorMsg  string     `json:"error_msg"`
+	}
+	if err := sonic.ConfigDefault.Unmarshal(body, &payload); err != nil {
+		return e
+	}
+
+	e.RequestID = payload.RequestID
+	if payload.Error != nil {
+		e.Code = codeString(payload.Error.Code)
+		e.Type = payload.Error.Type
+		e.Message = payload.Error.Message
+		return e
+	}
+
+	// 百度千帆的错误结构为 {"error_code":0,"error_msg":""}
+	if payload.ErrorCode != nil {
+		e.Code = codeString(payload.ErrorCode)
+		e.Message = payload.ErrorMsg
+		return e
+	}
+
+	e.Code = codeString(payload.Code)
+	e.Message = payload.Message
+	return e
+}
+
+// ChunkError 表示流式响应中无法解析的分片。
+// 它可以通过 errors.Is(err, errorx.InvalidOutput) 判断，Data 字段保留了分片的原始内容。
+type ChunkError struct {
+	Provider string // 服务商类型，如 openai、zhipu
+	Data     string // 分片的原始内容
+	Err      error  // 解析分片时的错误
+}
+
+// Error 实现 error 接口，返回可读的错误描述。
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("%s malformed chunk: %v, data=%s", e.Provider, e.Err, e.Data)
+}
+
+// Unwrap 返回 InvalidOutput，使 errors.Is(err, errorx.InvalidOutput) 判断生效。
+func (e *ChunkError) Unwrap() error {
+	return InvalidOutput
+}
+
+// StatusError 根据 HTTP 状态码返回对应的错误类别，无法归类时返回 nil。
+func StatusError(statusCode int) error {
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return InvalidRequest
+	case statusCode == http.StatusUnauthorized:
+		return Unauthorized
+	case statusCode == http.StatusForbidden:
+		return PermissionDenied
+	case statusCode == http.StatusNotFound:
+		return NotFound
+	case statusCode == http.StatusTooManyRequests:
+		return RateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ServerError
+	default:
+		return nil
+	}
+}
+
+// errorBody 是 {"error":{...}} 结构中的错误详情
+type errorBody struct {
+	Code    any    `json:"code"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// codeString 将数字或字符串形式的错误码统一转换为字符串
+func codeString(code any) string {
+	switch v := code.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
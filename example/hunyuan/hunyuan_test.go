@@ -0,0 +1,48 @@
+package hunyuan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_Completions(t *testing.T) {
+	secretID := os.Getenv("UNIAI_API_HUNYUAN_SECRET_ID")
+	secretKey := os.Getenv("UNIAI_API_HUNYUAN_SECRET_KEY")
+	if secretID == "" || secretKey == "" {
+		t.Fatal("UNIAI_API_HUNYUAN_SECRET_ID or UNIAI_API_HUNYUAN_SECRET_KEY is empty")
+	}
+
+	chat := uniai.New(
+		client.WithType(client.Hunyuan),
+		client.WithHost("https://hunyuan.tencentcloudapi.com"),
+		client.WithSecret(secretID, secretKey),
+	)
+
+	in := *request.NewRequest(
+		request.WithModel("hunyuan-lite"),
+		request.WithTopP(0.9),
+		request.WithStream(true),
+		request.WithMessages([]request.Messages{
+			request.NewMessage(request.MessageRoleSystem, "你是一位现代诗人，能够轻松的写出李白和杜甫的风格的诗词"),
+			request.NewMessage(request.MessageRoleUser, "请帮我写一首关于春天的诗"),
+		}),
+	)
+
+	resp, err := chat.Completions(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for item := range resp {
+		bs, _ := sonic.ConfigDefault.MarshalToString(item)
+		fmt.Println("resp.Item=", bs)
+	}
+}
@@ -0,0 +1,46 @@
+package uniai
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Images 接口定义了图像生成能力。
+type Images interface {
+	// Generate 根据描述生成图像。
+	// 对于异步接口（如通义万相），该方法会按指数退避轮询任务状态直到完成，
+	// 可以通过 ctx 的超时或取消来结束等待。
+	Generate(ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error)
+}
+
+// images 结构体实现了 Images 接口
+type images struct {
+	u *uniai
+}
+
+// Images 方法返回图像生成能力
+func (u *uniai) Images() Images {
+	return images{u: u}
+}
+
+// Generate 根据描述生成图像，客户端不支持图像生成时返回 errorx.NotSupported。
+// 请求的模型是 client.WithRoute 设置的别名时，请求会被转发给别名对应的服务商与模型。
+func (i images) Generate(ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error) {
+	if in.Prompt == "" {
+		return nil, errorx.InvalidInput
+	}
+
+	target, route, ok := i.u.route(in.Model)
+	if ok {
+		in.Model = route.Model
+	}
+	g, ok := target.getClient().(client.ImageGenerator)
+	if !ok {
+		return nil, errorx.NotSupported
+	}
+	return g.Images(*target.opts, ctx, in)
+}
@@ -0,0 +1,72 @@
+package ark
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/openai"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	// chatEndpoint 是火山方舟兼容 OpenAI 格式的对话接口
+	chatEndpoint = "/api/v3/chat/completions"
+	// embeddingEndpoint 是火山方舟兼容 OpenAI 格式的向量接口
+	embeddingEndpoint = "/api/v3/embeddings"
+)
+
+// ark 结构体实现了 client.IClient 接口，用于与火山方舟（豆包）服务进行交互。
+// 火山方舟的接口与 OpenAI 兼容，请求会被转发给 OpenAI 适配器，这里只处理接口地址与错误码的差异。
+type ark struct {
+	compatible client.IClient // OpenAI 兼容接口的客户端
+}
+
+// NewClient 创建并返回一个 ark 实例，该实例实现了 client.IClient 接口。
+// 火山方舟使用推理接入点 ID（ep-xxxx）作为模型名称，
+// 可以通过 client.WithModelMapping 将友好的模型名称映射为接入点 ID，响应中的模型名称会还原为友好的名称。
+func NewClient() client.IClient {
+	return &ark{compatible: openai.NewCompatibleClient(openai.Compatible{Provider: client.Ark, APIError: newAPIError})}
+}
+
+// SupportsResponseFormat 返回是否原生支持指定的输出格式。
+// 火山方舟兼容 OpenAI 的 json_object 与 json_schema 两种输出格式。
+func (ark) SupportsResponseFormat(formatType string) bool {
+	return formatType == request.ResponseFormatJSONObject || formatType == request.ResponseFormatJSONSchema
+}
+
+// Completions 方法用于获取补全建议，请求会被转发到火山方舟兼容 OpenAI 格式的接口。
+// 深度思考模型会在 delta.reasoning_content 中输出思考过程，在 delta.content 中输出最终回答。
+func (h ark) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = chatEndpoint
+	}
+	return h.compatible.Completions(opt, ctx, in)
+}
+
+// newAPIError 将火山方舟的错误响应转换为 APIError，并根据错误码归类。
+// 方舟的错误码示例：AuthenticationError、InvalidEndpointOrModel.NotFound、RateLimitExceeded.EndpointRPMExceeded。
+func newAPIError(statusCode int, body []byte) *errorx.APIError {
+	e := errorx.NewAPIError(client.Ark, statusCode, body)
+	switch code := e.Code; {
+	case strings.HasPrefix(code, "AuthenticationError"), strings.HasPrefix(code, "InvalidAccountStatus"):
+		e.Err = errorx.Unauthorized
+	case strings.HasPrefix(code, "AccessDenied"), strings.HasPrefix(code, "ModelNotOpen"), strings.HasPrefix(code, "OperationDenied"):
+		e.Err = errorx.PermissionDenied
+	case strings.HasPrefix(code, "InvalidEndpointOrModel"), strings.HasSuffix(code, ".NotFound"):
+		e.Err = errorx.NotFound
+	case strings.HasPrefix(code, "RateLimitExceeded"), strings.HasPrefix(code, "ServerOverloaded"):
+		e.Err = errorx.RateLimited
+	case strings.HasPrefix(code, "QuotaExceeded"), strings.HasPrefix(code, "AccountOverdue"):
+		e.Err = errorx.QuotaExceeded
+	case strings.Contains(code, "SensitiveContentDetected"):
+		e.Err = errorx.ContentFiltered
+	case strings.HasPrefix(code, "MissingParameter"), strings.HasPrefix(code, "InvalidParameter"):
+		e.Err = errorx.InvalidRequest
+	case strings.HasPrefix(code, "InternalServiceError"):
+		e.Err = errorx.ServerError
+	}
+	return e
+}
@@ -0,0 +1,69 @@
+package ark
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_CompletionsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"model":"ep-20240101-abcde"`) {
+			t.Errorf("model should be mapped to endpoint id, got %s", body)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"reasoning_content\":\"思考\",\"content\":\"\"}}]}\n\n"))
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"回答\"},\"finish_reason\":\"stop\"}]}\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	opt := client.NewOptions(client.WithHost(srv.URL), client.WithModelMapping(map[string]string{"doubao-pro": "ep-20240101-abcde"}))
+	in := *request.NewRequest(request.WithModel("doubao-pro"), request.WithStream(true))
+	out, err := NewClient().Completions(*opt, context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reasoning, content string
+	for item := range out {
+		if item.Model != "doubao-pro" {
+			t.Fatalf("model = %s, want doubao-pro", item.Model)
+		}
+		reasoning += item.Choices[0].Delta.ReasoningContent
+		content += item.Choices[0].Delta.Content
+	}
+	if reasoning != "思考" || content != "回答" {
+		t.Fatalf("reasoning = %q, content = %q", reasoning, content)
+	}
+}
+
+func Test_CompletionsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":"InvalidEndpointOrModel.NotFound","message":"The model or endpoint does not exist","type":"NotFound"}}`))
+	}))
+	defer srv.Close()
+
+	opt := client.NewOptions(client.WithHost(srv.URL))
+	_, err := NewClient().Completions(*opt, context.Background(), *request.NewRequest(request.WithModel("ep-missing")))
+
+	var apiErr *errorx.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "InvalidEndpointOrModel.NotFound" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(err, errorx.NotFound) {
+		t.Fatalf("error should be classified as not found: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package ark
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Embeddings 方法用于获取文本的向量，模型名称同样支持通过 client.WithModelMapping 映射为接入点 ID。
+func (h ark) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = embeddingEndpoint
+	}
+	return h.compatible.Embeddings(opt, ctx, in)
+}
+
+// EmbeddingBatchSize 返回单次请求允许的最大文本条数，火山方舟单次最多 256 条。
+func (ark) EmbeddingBatchSize(model string) int {
+	return 256
+}
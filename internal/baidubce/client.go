@@ -2,14 +2,23 @@ package baidubce
 
 import (
 	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
 	"github.com/jun3372/uniai/request"
 	"github.com/jun3372/uniai/response"
 )
 
 type baidubce struct{}
 
+// NewClient 创建并返回一个 baidubce 实例，该实例实现了 client.IClient 接口。
+// 调用方通过 Authorization 请求头传入千帆的 access_token。
 func NewClient() client.IClient {
 	return &baidubce{}
 }
@@ -27,5 +36,69 @@ func NewClient() client.IClient {
 //	chan response.Response - 一个channel，用于接收补全响应的结果。
 //	error - 如果在请求过程中出现错误，将返回错误信息。
 func (h baidubce) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
-	panic("not implemented") // TODO: Implement
+	return nil, errorx.NotSupported
+}
+
+// Embeddings 方法用于获取文本的向量。
+// 千帆的向量接口路径中包含模型名称，如 embedding-v1、bge_large_zh、tao_8k；千帆只返回浮点数数组，不支持 base64 格式。
+func (h baidubce) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+	if in.EncodingFormat == request.EncodingFormatBase64 {
+		return nil, errors.Wrap(errorx.NotSupported, "baidubce embeddings do not support base64 encoding format")
+	}
+
+	// 定义默认的API端点
+	endpoint := "/rpc/2.0/ai_custom/v1/wenxinworkshop/embeddings/" + url.PathEscape(in.Model)
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	// access_token 通过查询参数传递，不再重复放在请求头中。
+	uri, header := opt.Host+endpoint, opt.Header
+	if token := accessToken(opt); token != "" {
+		uri += "?access_token=" + url.QueryEscape(token)
+		header = header.Clone()
+		header.Del("Authorization")
+	}
+
+	var resp Embeddings
+	body := map[string]any{"input": in.Input}
+	if in.User != "" {
+		body["user_id"] = in.User
+	}
+	if err := httpx.PostJSON(ctx, opt.HTTP(), client.Baidubce, uri, header, body, &resp); err != nil {
+		return nil, err
+	}
+
+	// 千帆在出错时同样返回 200，错误信息位于 error_code 与 error_msg 中。
+	if resp.ErrorCode != 0 {
+		return nil, &errorx.APIError{Provider: client.Baidubce, StatusCode: 200, Code: strconv.Itoa(resp.ErrorCode), Message: resp.ErrorMsg}
+	}
+
+	out := &response.EmbeddingResponse{
+		Object: "list",
+		Model:  in.Model,
+		Usage:  &response.Usage{PromptTokens: resp.Usage.PromptTokens, TotalTokens: resp.Usage.TotalTokens},
+	}
+	for _, item := range resp.Data {
+		out.Data = append(out.Data, response.Embedding{Object: item.Object, Index: item.Index, Embedding: item.Embedding})
+	}
+	return out, nil
+}
+
+// EmbeddingBatchSize 返回单次请求允许的最大文本条数，千帆单次最多 16 条。
+func (baidubce) EmbeddingBatchSize(model string) int {
+	return 16
+}
+
+// accessToken 从 Authorization 请求头中取出千帆的 access_token，兼容带 Bearer 前缀的写法
+func accessToken(opt client.Options) string {
+	token := strings.TrimSpace(opt.Header.Get("Authorization"))
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	return token
 }
@@ -0,0 +1,36 @@
+package baidubce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func Test_Embeddings(t *testing.T) {
+	srv := uniaitest.NewServer(t, uniaitest.FormatQianfan)
+	opt := client.NewOptions(client.WithHost(srv.URL), client.AddHeader("Authorization", "Bearer token-1"))
+
+	in := *request.NewEmbeddingRequest(request.WithEmbeddingModel("embedding-v1"), request.WithInput("你好", "hi"))
+	resp, err := NewClient().Embeddings(*opt, context.Background(), in)
+	if err != nil || len(resp.Data) != 2 || resp.Data[1].Embedding[0] != 2 {
+		t.Fatalf("Embeddings = %+v, %v", resp, err)
+	}
+
+	// access_token 只出现在查询参数中
+	recorded := srv.Request(t, 0)
+	if recorded.Path != "/rpc/2.0/ai_custom/v1/wenxinworkshop/embeddings/embedding-v1" || recorded.Query.Get("access_token") != "token-1" {
+		t.Fatalf("unexpected request: %s?%s", recorded.Path, recorded.Query.Encode())
+	}
+	recorded.AssertHeader(t, "Authorization", "")
+
+	in.EncodingFormat = request.EncodingFormatBase64
+	if _, err := NewClient().Embeddings(*opt, context.Background(), in); !errors.Is(err, errorx.NotSupported) {
+		t.Fatalf("base64: err = %v, want NotSupported", err)
+	}
+}
@@ -27,3 +27,21 @@ type SearchResult struct {
 	Url   string `json:"url"`   // 搜索结果地址
 	Title string `json:"title"` // 搜索结果标题
 }
+
+// Embeddings 是千帆向量接口的响应
+type Embeddings struct {
+	ID        string          `json:"id"`         // 本次请求的 id
+	Object    string          `json:"object"`     // 回包类型，固定为 embedding_list
+	Created   int             `json:"created"`    // 时间戳
+	Data      []EmbeddingData `json:"data"`       // 向量结果
+	Usage     Usage           `json:"usage"`      // token统计信息
+	ErrorCode int             `json:"error_code"` // 错误码
+	ErrorMsg  string          `json:"error_msg"`  // 错误描述
+}
+
+// EmbeddingData 是千帆返回的一条向量
+type EmbeddingData struct {
+	Object    string    `json:"object"`    // 固定为 embedding
+	Embedding []float32 `json:"embedding"` // 向量
+	Index     int       `json:"index"`     // 序号
+}
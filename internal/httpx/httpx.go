@@ -0,0 +1,102 @@
+// Package httpx 提供各服务商客户端共用的 HTTP 请求工具。
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+)
+
+// NewRequest 创建一个携带请求头与上下文的 HTTP 请求。
+// 请求头中每个键仅取第一个值，与 Completions 中的处理方式保持一致。
+func NewRequest(ctx context.Context, method, uri string, header http.Header, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, errorx.InvalidInput
+	}
+
+	for k, v := range header {
+		if len(v) > 0 {
+			req.Header.Set(k, v[0])
+		}
+	}
+	return req, nil
+}
+
+// PostJSON 使用 HTTP 客户端 c 以 JSON 格式发送 POST 请求，并将响应体解码到 out 中。
+// 响应状态码不是 200 时返回 *errorx.APIError。
+func PostJSON(ctx context.Context, c *http.Client, provider, uri string, header http.Header, in, out any) error {
+	body, err := sonic.ConfigDefault.Marshal(in)
+	if err != nil {
+		return errorx.InvalidInput
+	}
+
+	req, err := NewRequest(ctx, http.MethodPost, uri, header, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return Do(c, req, provider, out)
+}
+
+// Do 使用 HTTP 客户端 c 发送请求，并将响应体解码到 out 中。
+// 响应状态码不是 200 时返回 *errorx.APIError，out 为 nil 时忽略响应体。
+func Do(c *http.Client, req *http.Request, provider string, out any) error {
+	data, err := Bytes(c, req, provider)
+	if err != nil || out == nil {
+		return err
+	}
+	return sonic.ConfigDefault.Unmarshal(data, out)
+}
+
+// Bytes 发送 HTTP 请求并返回完整的响应体。
+// 响应状态码不是 200 时返回 *errorx.APIError。
+func Bytes(c *http.Client, req *http.Request, provider string) ([]byte, error) {
+	resp, err := send(c, req, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Stream 发送 HTTP 请求，并将响应体边接收边写入 w，返回写入的字节数。
+// 响应状态码不是 200 时返回 *errorx.APIError，此时不会向 w 写入任何内容。
+func Stream(c *http.Client, req *http.Request, provider string, w io.Writer) (int64, error) {
+	resp, err := send(c, req, provider)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
+
+// send 发送 HTTP 请求，响应状态码不是 200 时读取响应体并返回 *errorx.APIError。
+func send(c *http.Client, req *http.Request, provider string) (*http.Response, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, errorx.NewAPIError(provider, resp.StatusCode, data)
+	}
+	return resp, nil
+}
@@ -0,0 +1,206 @@
+package hunyuan
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/stream"
+	"github.com/jun3372/uniai/internal/tc3"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	// service 是混元在腾讯云 API 中的服务名
+	service = "hunyuan"
+	// version 是混元接口的版本号
+	version = "2023-09-01"
+	// actionChatCompletions 是对话接口的 Action 名称
+	actionChatCompletions = "ChatCompletions"
+)
+
+// hunyuan 结构体实现了 client.IClient 接口，用于与腾讯混元服务进行交互。
+type hunyuan struct{}
+
+// NewClient 创建并返回一个 hunyuan 实例，该实例实现了 client.IClient 接口。
+// 调用方需通过 client.WithSecret 传入腾讯云的 SecretId 与 SecretKey，
+// 客户端会使用 TC3-HMAC-SHA256 对每个请求签名。
+func NewClient() client.IClient {
+	return &hunyuan{}
+}
+
+// Completions 方法用于获取补全建议。
+// 它根据提供的选项、上下文和请求信息，返回一个响应的channel和可能的错误。
+// 参数:
+//
+//	opt Options - 补全请求的选项，包含了请求的具体配置。
+//	ctx context.Context - 请求的上下文，用于控制请求的取消和超时等。
+//	in request.Request - 补全请求的对象，包含了请求的具体内容。
+//
+// 返回值:
+//
+//	chan response.Response - 一个channel，用于接收补全响应的结果。
+//	error - 如果在请求过程中出现错误，将返回错误信息。
+func (h hunyuan) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+	if opt.SecretID == "" || opt.SecretKey == "" {
+		return nil, errorx.InvalidInput
+	}
+
+	// 腾讯云 API 3.0 的所有接口都使用根路径，由 X-TC-Action 区分
+	endpoint := "/"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	// 将请求信息转换为混元的请求结构并序列化。
+	body, err := sonic.ConfigDefault.Marshal(newRequest(in))
+	if err != nil {
+		return nil, errorx.InvalidInput
+	}
+
+	// 构建请求的URI。
+	uri := opt.Host + endpoint
+	req, err := h.newHTTPRequest(opt, actionChatCompletions, uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 初始化一个可取消的上下文ctx，用于后续操作中根据需要取消相应的协程或操作。
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	// 设置请求的上下文。
+	req = req.WithContext(ctx)
+	// 发送HTTP请求并获取响应。
+	resp, err := opt.HTTP().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
+
+	// 腾讯云在出错时同样返回 200，错误信息位于 Response.Error 中；
+	// 流式请求出错时返回的是普通 JSON 而不是事件流。
+	if resp.StatusCode != http.StatusOK || !in.Stream || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+
+		var env envelope
+		if err = sonic.ConfigDefault.Unmarshal(data, &env); err != nil || env.Response.Error != nil || resp.StatusCode != http.StatusOK {
+			slog.Error("hunyuan Completions error", slog.String("uri", uri), slog.String("payload", string(body)), slog.String("response", string(data)))
+			err = newAPIError(resp.StatusCode, env.Response, data)
+			return nil, err
+		}
+
+		out := make(chan response.Response, 1)
+		out <- env.Response.toResponse(in.Model, in.Stream)
+		close(out)
+		cancel()
+		return out, nil
+	}
+
+	// 由 stream.Start 负责通道与响应体的生命周期。
+	out := stream.Start(ctx, cancel, resp.Body, in.ChannelMaxLength, client.Hunyuan, func(send stream.Send) error {
+		return h.handlerStream(resp.Body, in.Model, stream.NewOptions(client.Hunyuan, opt), send)
+	})
+	return out, nil
+}
+
+// newHTTPRequest 创建一个已签名的腾讯云 API 3.0 请求。
+func (hunyuan) newHTTPRequest(opt client.Options, action, uri string, body []byte) (*http.Request, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errorx.InvalidHost
+	}
+
+	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, errorx.InvalidInput
+	}
+
+	// 添加自定义请求头到HTTP请求。
+	for k, v := range opt.Header {
+		req.Header.Add(k, v[0])
+	}
+	req.Header.Set("X-TC-Action", action)
+	req.Header.Set("X-TC-Version", version)
+	if opt.Region != "" {
+		req.Header.Set("X-TC-Region", opt.Region)
+	}
+
+	req.Host = u.Host
+	tc3.NewSigner(opt.SecretID, opt.SecretKey, service).Sign(req, body, time.Now())
+	return req, nil
+}
+
+// handlerStream 处理混元的流式响应。
+// 混元的流式分片使用大驼峰字段，最后一个分片的 FinishReason 不为空，且没有 [DONE] 结束标记。
+// 参数:
+//
+//	reader: 一个io.ReadCloser接口，用于读取流数据。
+//	model: 请求的模型名称，混元的分片中不包含模型名称。
+//	opts: 流读取器的选项。
+//	send: 将处理结果输出给调用方的函数。
+//
+// 返回值:
+//
+//	error - 读取过程中出现的错误，如格式错误的分片、错误分片、空闲超时或被截断的流。
+func (hunyuan) handlerStream(reader io.ReadCloser, model string, opts stream.Options, send stream.Send) error {
+	return stream.Read(reader, opts, func(data string) (response.Response, error) {
+		var chunk Response
+		if err := sonic.ConfigDefault.UnmarshalFromString(data, &chunk); err != nil {
+			return response.Response{}, err
+		}
+		if chunk.Error != nil {
+			return response.Response{}, newAPIError(http.StatusOK, chunk, []byte(data))
+		}
+		return chunk.toResponse(model, true), nil
+	}, send)
+}
+
+// newAPIError 将腾讯云的错误信息转换为 APIError
+func newAPIError(statusCode int, resp Response, body []byte) *errorx.APIError {
+	e := &errorx.APIError{Provider: client.Hunyuan, StatusCode: statusCode, RequestID: resp.RequestId, Body: string(body), Err: errorx.StatusError(statusCode)}
+	if resp.Error == nil {
+		return e
+	}
+
+	e.Code = resp.Error.Code
+	e.Message = resp.Error.Message
+	switch {
+	case strings.HasPrefix(e.Code, "AuthFailure"):
+		e.Err = errorx.Unauthorized
+	case strings.HasPrefix(e.Code, "UnauthorizedOperation"):
+		e.Err = errorx.PermissionDenied
+	case strings.HasPrefix(e.Code, "ResourceNotFound"):
+		e.Err = errorx.NotFound
+	case strings.HasPrefix(e.Code, "RequestLimitExceeded"):
+		e.Err = errorx.RateLimited
+	case strings.HasPrefix(e.Code, "InternalError"), strings.HasPrefix(e.Code, "ResourceInsufficient"), strings.HasPrefix(e.Code, "FailedOperation.EngineRequestTimeout"):
+		e.Err = errorx.ServerError
+	case strings.HasPrefix(e.Code, "InvalidParameter"), strings.HasPrefix(e.Code, "MissingParameter"):
+		e.Err = errorx.InvalidRequest
+	}
+	return e
+}
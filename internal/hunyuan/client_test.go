@@ -0,0 +1,122 @@
+package hunyuan
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func Test_CompletionsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-TC-Action") != actionChatCompletions || r.Header.Get("X-TC-Region") != "ap-guangzhou" || !strings.HasPrefix(r.Header.Get("Authorization"), "TC3-HMAC-SHA256 Credential=AKIDtest/") {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var in Request
+		if err := sonic.ConfigDefault.Unmarshal(body, &in); err != nil {
+			t.Fatal(err)
+		}
+		if in.Model != "hunyuan-pro" || !in.Stream || in.ToolChoice != "auto" || len(in.Messages) != 3 || in.Messages[1].ToolCalls[0].Id != "call_0" || in.Messages[2].ToolCallId != "call_0" {
+			t.Errorf("unexpected request: %s", body)
+		}
+		if len(in.Tools) != 1 || in.Tools[0].Function.Name != "weather" || in.Tools[0].Function.Parameters != `{"type":"object"}` {
+			t.Errorf("tools should use json string parameters: %s", body)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"Id\":\"1\",\"Choices\":[{\"Delta\":{\"Role\":\"assistant\",\"ReasoningContent\":\"思考\"}}]}\n\n"))
+		_, _ = w.Write([]byte("data: {\"Id\":\"1\",\"Choices\":[{\"Delta\":{\"ToolCalls\":[{\"Id\":\"call_1\",\"Type\":\"function\",\"Index\":0,\"Function\":{\"Name\":\"weather\",\"Arguments\":\"{}\"}}]}}]}\n\n"))
+		_, _ = w.Write([]byte("data: {\"Id\":\"1\",\"Choices\":[{\"FinishReason\":\"tool_calls\",\"Delta\":{\"Content\":\"\"}}],\"Usage\":{\"PromptTokens\":3,\"CompletionTokens\":2,\"TotalTokens\":5}}\n\n"))
+	}))
+	defer srv.Close()
+
+	call := request.ToolCall{ID: "call_0", Type: "function", Function: request.FunctionCall{Name: "weather", Arguments: "{}"}}
+	assistant := request.NewAssistantMessage("")
+	assistant.ToolCalls = []request.ToolCall{call}
+	in := *request.NewRequest(
+		request.WithModel("hunyuan-pro"),
+		request.WithStream(true),
+		request.WithMessages([]request.Messages{request.NewUserMessage("天气"), assistant, request.NewToolMessage("call_0", "晴")}),
+		request.WithTools([]request.Tool{request.NewFunctionTool("weather", "查询天气", map[string]any{"type": "object"})}),
+		request.WithToolChoice("auto"),
+	)
+	opt := client.NewOptions(client.WithHost(srv.URL), client.WithSecret("AKIDtest", "secret"), client.WithRegion("ap-guangzhou"))
+	out, err := NewClient().Completions(*opt, context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc := response.NewAccumulator()
+	for item := range out {
+		if item.Model != "hunyuan-pro" || item.Object != "chat.completion.chunk" {
+			t.Fatalf("unexpected chunk: %+v", item)
+		}
+		acc.Add(item)
+	}
+	msg := acc.Message()
+	if err := acc.Err(); err != nil || msg.ReasoningContent != "思考" || len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "weather" {
+		t.Fatalf("message = %+v, err = %v", msg, err)
+	}
+	if acc.FinishReason() != "tool_calls" || acc.Usage().TotalTokens != 5 {
+		t.Fatalf("finish reason = %s, usage = %+v", acc.FinishReason(), acc.Usage())
+	}
+}
+
+func Test_CompletionsError(t *testing.T) {
+	// 腾讯云在出错时返回 200，流式请求出错时返回普通 JSON 而不是事件流
+	body := `{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"签名错误"},"RequestId":"req-1"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	opt := client.NewOptions(client.WithHost(srv.URL), client.WithSecret("AKIDtest", "secret"))
+	for _, stream := range []bool{false, true} {
+		_, err := NewClient().Completions(*opt, context.Background(), *request.NewRequest(request.WithModel("hunyuan-pro"), request.WithStream(stream)))
+
+		var apiErr *errorx.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != "AuthFailure.SignatureFailure" || apiErr.RequestID != "req-1" || apiErr.StatusCode != http.StatusOK {
+			t.Fatalf("stream=%v: unexpected error: %v", stream, err)
+		}
+		if !errors.Is(err, errorx.Unauthorized) {
+			t.Fatalf("stream=%v: error should be classified as unauthorized: %v", stream, err)
+		}
+	}
+
+	if _, err := NewClient().Completions(*client.NewOptions(client.WithHost(srv.URL)), context.Background(), *request.NewRequest()); !errors.Is(err, errorx.InvalidInput) {
+		t.Fatalf("missing secret: err = %v", err)
+	}
+}
+
+func Test_Embeddings(t *testing.T) {
+	srv := uniaitest.NewServer(t, uniaitest.FormatTC3)
+	opt := client.NewOptions(client.WithHost(srv.URL), client.WithSecret("AKIDtest", "secret"))
+
+	// 混元单次只接受一条文本，多条文本依次请求后合并
+	in := *request.NewEmbeddingRequest(request.WithEmbeddingModel("hunyuan-embedding"), request.WithInput("a", "bb"))
+	resp, err := NewClient().Embeddings(*opt, context.Background(), in)
+	if err != nil || len(resp.Data) != 2 || resp.Data[1].Index != 1 || resp.Data[1].Embedding[0] != 2 || resp.Usage.TotalTokens != 3 {
+		t.Fatalf("Embeddings = %+v, %v", resp, err)
+	}
+	srv.AssertRequestCount(t, 2)
+	srv.Request(t, 0).AssertHeader(t, "X-TC-Action", actionGetEmbedding)
+
+	in.EncodingFormat = request.EncodingFormatBase64
+	if _, err := NewClient().Embeddings(*opt, context.Background(), in); !errors.Is(err, errorx.NotSupported) {
+		t.Fatalf("base64: err = %v, want NotSupported", err)
+	}
+}
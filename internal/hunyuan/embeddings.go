@@ -0,0 +1,113 @@
+package hunyuan
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// actionGetEmbedding 是向量化接口的 Action 名称
+const actionGetEmbedding = "GetEmbedding"
+
+// embeddingRequest 是混元 GetEmbedding 接口的请求结构
+type embeddingRequest struct {
+	Input string `json:"Input"` // 需要向量化的文本
+}
+
+// embeddingResponse 是混元 GetEmbedding 接口的响应结构
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"Embedding"` // 向量
+		Index     int       `json:"Index"`     // 序号
+		Object    string    `json:"Object"`    // 对象类型
+	} `json:"Data"`
+	Usage *Usage `json:"Usage"` // Token 统计信息
+}
+
+// Embeddings 方法用于获取文本的向量。
+// 混元单次请求只接受一条文本，多条文本会被依次请求并合并结果；混元只返回浮点数数组，不支持 base64 格式。
+func (h hunyuan) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+	if opt.SecretID == "" || opt.SecretKey == "" {
+		return nil, errorx.InvalidInput
+	}
+	if in.EncodingFormat == request.EncodingFormatBase64 {
+		return nil, errors.Wrap(errorx.NotSupported, "hunyuan embeddings do not support base64 encoding format")
+	}
+
+	endpoint := "/"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	out := &response.EmbeddingResponse{Object: "list", Model: in.Model, Usage: &response.Usage{}}
+	for i, input := range in.Input {
+		var resp embeddingResponse
+		if err := h.call(ctx, opt, actionGetEmbedding, opt.Host+endpoint, embeddingRequest{Input: input}, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Data {
+			out.Data = append(out.Data, response.Embedding{Object: "embedding", Index: i, Embedding: item.Embedding})
+		}
+		if resp.Usage != nil {
+			out.Usage.PromptTokens += resp.Usage.PromptTokens
+			out.Usage.TotalTokens += resp.Usage.TotalTokens
+		}
+	}
+	return out, nil
+}
+
+// EmbeddingBatchSize 返回单次请求允许的最大文本条数，混元单次只接受一条文本。
+func (hunyuan) EmbeddingBatchSize(model string) int {
+	return 1
+}
+
+// call 调用腾讯云 API 3.0 的非流式接口，并将 Response 字段解码到 out 中。
+func (h hunyuan) call(ctx context.Context, opt client.Options, action, uri string, in, out any) error {
+	body, err := sonic.ConfigDefault.Marshal(in)
+	if err != nil {
+		return errorx.InvalidInput
+	}
+
+	req, err := h.newHTTPRequest(opt, action, uri, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := opt.HTTP().Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var env envelope
+	if err := sonic.ConfigDefault.Unmarshal(data, &env); err != nil || env.Response.Error != nil || resp.StatusCode != http.StatusOK {
+		return newAPIError(resp.StatusCode, env.Response, data)
+	}
+
+	var result struct {
+		Response any `json:"Response"`
+	}
+	result.Response = out
+	return sonic.ConfigDefault.Unmarshal(data, &result)
+}
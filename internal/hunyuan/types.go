@@ -0,0 +1,188 @@
+package hunyuan
+
+import (
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Request 是混元 ChatCompletions 接口的请求结构，字段采用腾讯云 API 的大驼峰命名。
+type Request struct {
+	Model       string    `json:"Model"`                 // 模型名称，如 hunyuan-pro
+	Messages    []Message `json:"Messages"`              // 聊天上下文信息
+	Stream      bool      `json:"Stream,omitempty"`      // 是否流式输出
+	TopP        *float32  `json:"TopP,omitempty"`        // 说明：影响输出文本的多样性，为 nil 时使用默认值
+	Temperature *float32  `json:"Temperature,omitempty"` // 说明：较高的数值会使输出更加随机，为 nil 时使用默认值
+	Tools       []Tool    `json:"Tools,omitempty"`       // 可调用的工具列表
+	ToolChoice  string    `json:"ToolChoice,omitempty"`  // 工具使用选项：none、auto
+}
+
+// Message 是混元的会话消息
+type Message struct {
+	Role             string     `json:"Role"`                       // 角色：system、user、assistant、tool
+	Content          string     `json:"Content,omitempty"`          // 消息内容
+	ReasoningContent string     `json:"ReasoningContent,omitempty"` // 推理模型的思考过程，仅在响应中返回
+	ToolCalls        []ToolCall `json:"ToolCalls,omitempty"`        // 模型生成的工具调用
+	ToolCallId       string     `json:"ToolCallId,omitempty"`       // 工具消息对应的调用标识
+}
+
+// Tool 是混元的工具定义
+type Tool struct {
+	Type     string       `json:"Type"`     // 工具类型，当前仅支持 function
+	Function ToolFunction `json:"Function"` // 函数定义
+}
+
+// ToolFunction 是混元的函数定义，参数需以 JSON 字符串的形式传入
+type ToolFunction struct {
+	Name        string `json:"Name"`                  // 函数名称
+	Parameters  string `json:"Parameters"`            // 函数参数的 JSON Schema 字符串
+	Description string `json:"Description,omitempty"` // 函数描述
+}
+
+// ToolCall 是混元返回的工具调用
+type ToolCall struct {
+	Id       string           `json:"Id"`       // 工具调用标识
+	Type     string           `json:"Type"`     // 工具调用类型
+	Function ToolCallFunction `json:"Function"` // 被调用的函数
+	Index    int              `json:"Index"`    // 流式输出中的工具调用序号
+}
+
+// ToolCallFunction 是混元返回的函数调用
+type ToolCallFunction struct {
+	Name      string `json:"Name"`      // 函数名称
+	Arguments string `json:"Arguments"` // JSON 格式的函数参数
+}
+
+// Response 是混元的响应结构，流式分片直接使用该结构，非流式响应包裹在 Response 字段中。
+type Response struct {
+	Id        string   `json:"Id"`        // 本次请求的标识
+	Note      string   `json:"Note"`      // 免责声明
+	Created   int      `json:"Created"`   // Unix 时间戳，单位为秒
+	Choices   []Choice `json:"Choices"`   // 回复内容
+	Usage     *Usage   `json:"Usage"`     // Token 统计信息
+	RequestId string   `json:"RequestId"` // 腾讯云请求标识
+	Error     *Error   `json:"Error"`     // 错误信息
+}
+
+// Choice 是混元返回的一条回复
+type Choice struct {
+	FinishReason string   `json:"FinishReason"` // 结束标志：stop、tool_calls、sensitive
+	Delta        *Message `json:"Delta"`        // 流式输出的增量内容
+	Message      *Message `json:"Message"`      // 非流式输出的完整内容
+}
+
+// Usage 是混元的 Token 统计信息
+type Usage struct {
+	PromptTokens     int `json:"PromptTokens"`     // 输入 Token 数量
+	CompletionTokens int `json:"CompletionTokens"` // 输出 Token 数量
+	TotalTokens      int `json:"TotalTokens"`      // Token 总数
+}
+
+// Error 是腾讯云 API 3.0 的错误信息
+type Error struct {
+	Code    string `json:"Code"`    // 错误码，如 AuthFailure.SignatureFailure
+	Message string `json:"Message"` // 错误描述
+}
+
+// envelope 是腾讯云 API 3.0 非流式响应的外层结构
+type envelope struct {
+	Response Response `json:"Response"`
+}
+
+// newRequest 将统一的请求结构转换为混元的请求结构
+func newRequest(in request.Request) Request {
+	out := Request{
+		Model:       in.Model,
+		Stream:      in.Stream,
+		TopP:        in.TopP,
+		Temperature: in.Temperature,
+		Messages:    make([]Message, 0, len(in.Messages)),
+	}
+
+	if choice, ok := in.ToolChoice.(string); ok {
+		out.ToolChoice = choice
+	}
+
+	for _, tool := range in.Tools {
+		if tool.Type != request.ToolTypeFunction || tool.Function == nil {
+			continue
+		}
+
+		parameters, _ := sonic.ConfigDefault.MarshalToString(tool.Function.Parameters)
+		out.Tools = append(out.Tools, Tool{
+			Type: tool.Type,
+			Function: ToolFunction{
+				Name:        tool.Function.Name,
+				Parameters:  parameters,
+				Description: tool.Function.Description,
+			},
+		})
+	}
+
+	for _, msg := range in.Messages {
+		m := Message{Role: msg.Role, Content: msg.Content, ToolCallId: msg.ToolCallID}
+		for _, call := range msg.ToolCalls {
+			m.ToolCalls = append(m.ToolCalls, ToolCall{
+				Id:       call.ID,
+				Type:     call.Type,
+				Function: ToolCallFunction{Name: call.Function.Name, Arguments: call.Function.Arguments},
+			})
+		}
+		out.Messages = append(out.Messages, m)
+	}
+	return out
+}
+
+// toResponse 将混元的响应转换为统一的响应结构
+func (r Response) toResponse(model string, stream bool) response.Response {
+	out := response.Response{
+		ID:        r.Id,
+		Object:    "chat.completion",
+		Created:   r.Created,
+		Model:     model,
+		RequestID: r.RequestId,
+		Choices:   make([]response.Choices, 0, len(r.Choices)),
+	}
+	if stream {
+		out.Object = "chat.completion.chunk"
+	}
+
+	if r.Usage != nil {
+		out.Usage = &response.Usage{
+			PromptTokens:     r.Usage.PromptTokens,
+			CompletionTokens: r.Usage.CompletionTokens,
+			TotalTokens:      r.Usage.TotalTokens,
+		}
+	}
+
+	for i, c := range r.Choices {
+		choice := response.Choices{Index: i, FinishReason: c.FinishReason}
+		if c.Delta != nil {
+			choice.Delta = &response.Delta{Role: c.Delta.Role, Content: c.Delta.Content, ReasoningContent: c.Delta.ReasoningContent, ToolCalls: toToolCalls(c.Delta.ToolCalls)}
+		}
+		if c.Message != nil {
+			choice.Message = &response.Message{Role: c.Message.Role, Content: c.Message.Content, ReasoningContent: c.Message.ReasoningContent, ToolCalls: toToolCalls(c.Message.ToolCalls)}
+		}
+		out.Choices = append(out.Choices, choice)
+	}
+	return out
+}
+
+// toToolCalls 将混元的工具调用转换为统一的工具调用结构
+func toToolCalls(calls []ToolCall) []response.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	out := make([]response.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		out = append(out, response.ToolCall{
+			Index:    call.Index,
+			ID:       call.Id,
+			Type:     call.Type,
+			Function: response.FunctionCall{Name: call.Function.Name, Arguments: call.Function.Arguments},
+		})
+	}
+	return out
+}
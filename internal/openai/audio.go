@@ -0,0 +1,138 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Transcriptions 方法用于将音频转换为文字，对应 OpenAI 的 /v1/audio/transcriptions 接口。
+// 音频以 multipart/form-data 的形式上传，兼容 Whisper 的本地服务（如 faster-whisper-server）也可以使用该方法。
+func (openai) Transcriptions(opt client.Options, ctx context.Context, in request.TranscriptionRequest) (*response.TranscriptionResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+	if in.File == nil {
+		return nil, errorx.InvalidInput
+	}
+
+	// 定义默认的API端点
+	endpoint := "/v1/audio/transcriptions"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	body, contentType := newTranscriptionBody(in)
+	req, err := httpx.NewRequest(ctx, http.MethodPost, opt.Host+endpoint, opt.Header, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	data, err := httpx.Bytes(opt.HTTP(), req, client.OpenAI)
+	if err != nil {
+		return nil, err
+	}
+
+	// text、srt、vtt 格式返回的是纯文本，直接作为识别结果。
+	switch in.ResponseFormat {
+	case request.TranscriptionFormatText, request.TranscriptionFormatSRT, request.TranscriptionFormatVTT:
+		return &response.TranscriptionResponse{Text: string(data)}, nil
+	}
+
+	var resp response.TranscriptionResponse
+	if err := sonic.ConfigDefault.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// newTranscriptionBody 将语音识别请求编码为 multipart/form-data，返回请求体与对应的 Content-Type。
+// 请求体在发送时由协程边读取音频边编码，大文件不会整体读入内存；编码失败时读取请求体会返回对应的错误。
+func newTranscriptionBody(in request.TranscriptionRequest) (io.ReadCloser, string) {
+	name := in.FileName
+	if name == "" {
+		name = "audio.wav"
+	}
+
+	fields := [][2]string{
+		{"model", in.Model},
+		{"language", in.Language},
+		{"prompt", in.Prompt},
+		{"response_format", in.ResponseFormat},
+	}
+	if in.Temperature != nil {
+		fields = append(fields, [2]string{"temperature", strconv.FormatFloat(float64(*in.Temperature), 'f', -1, 32)})
+	}
+	for _, granularity := range in.TimestampGranularities {
+		fields = append(fields, [2]string{"timestamp_granularities[]", granularity})
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(func() error {
+			for _, field := range fields {
+				if field[1] == "" {
+					continue
+				}
+				if err := w.WriteField(field[0], field[1]); err != nil {
+					return err
+				}
+			}
+
+			part, err := w.CreateFormFile("file", name)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, in.File); err != nil {
+				return err
+			}
+			return w.Close()
+		}())
+	}()
+	return pr, w.FormDataContentType()
+}
+
+// Speech 方法用于将文字合成为语音，对应 OpenAI 的 /v1/audio/speech 接口。
+// 音频数据会边接收边写入 w，返回写入的字节数。
+func (openai) Speech(opt client.Options, ctx context.Context, in request.SpeechRequest, w io.Writer) (int64, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return 0, errorx.InvalidHost
+	}
+	if in.Input == "" {
+		return 0, errorx.InvalidInput
+	}
+
+	// 定义默认的API端点
+	endpoint := "/v1/audio/speech"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	body, err := sonic.ConfigDefault.Marshal(in)
+	if err != nil {
+		return 0, errorx.InvalidInput
+	}
+
+	req, err := httpx.NewRequest(ctx, http.MethodPost, opt.Host+endpoint, opt.Header, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return httpx.Stream(opt.HTTP(), req, client.OpenAI, w)
+}
@@ -5,19 +5,28 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
 
-	"github.com/alevinval/sse/pkg/decoder"
 	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
 
 	"github.com/jun3372/uniai/client"
 	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/stream"
 	"github.com/jun3372/uniai/request"
 	"github.com/jun3372/uniai/response"
 )
 
 // openai 结构体实现了 client.IClient 接口，用于与 OpenAI 服务进行交互。
-type openai struct{}
+type openai struct {
+	compatible Compatible
+}
+
+// Compatible 描述兼容 OpenAI 接口的服务与 OpenAI 之间的差异。
+// 接口格式与 OpenAI 一致的服务商适配器可以基于 NewCompatibleClient 实现，只需处理地址、鉴权与模型名称等差异。
+type Compatible struct {
+	Provider string                                             // 服务商类型，用于错误信息与日志
+	APIError func(statusCode int, body []byte) *errorx.APIError // 将错误响应转换为 APIError，默认使用 errorx.NewAPIError
+}
 
 // NewClient 创建并返回一个 openai 实例，该实例实现了 client.IClient 接口。
 // 该函数是对外的接口，用于初始化 OpenAI 客户端。
@@ -25,7 +34,27 @@ type openai struct{}
 //
 //	*openai: 实现了 client.IClient 接口的实例，用于与 OpenAI 服务进行交互。
 func NewClient() client.IClient {
-	return &openai{}
+	return NewCompatibleClient(Compatible{Provider: client.OpenAI})
+}
+
+// NewCompatibleClient 创建一个用于兼容 OpenAI 接口的服务的客户端，错误信息与日志使用 c 中的服务商类型。
+func NewCompatibleClient(c Compatible) client.IClient {
+	if c.Provider == "" {
+		c.Provider = client.OpenAI
+	}
+	if c.APIError == nil {
+		provider := c.Provider
+		c.APIError = func(statusCode int, body []byte) *errorx.APIError {
+			return errorx.NewAPIError(provider, statusCode, body)
+		}
+	}
+	return &openai{compatible: c}
+}
+
+// SupportsResponseFormat 返回是否原生支持指定的输出格式。
+// OpenAI 原生支持 json_object 与 json_schema 两种输出格式。
+func (openai) SupportsResponseFormat(formatType string) bool {
+	return formatType == request.ResponseFormatJSONObject || formatType == request.ResponseFormatJSONSchema
 }
 
 // Completions 方法用于获取补全建议。
@@ -53,6 +82,10 @@ func (h openai) Completions(opt client.Options, ctx context.Context, in request.
 		endpoint = in.Endpoint
 	}
 
+	// 按映射替换模型名称，并在响应中还原为调用方使用的名称。
+	model := in.Model
+	in.Model = MapModel(opt.ModelMapping, in.Model)
+
 	// 将请求信息序列化为字符串形式。
 	payload, _ := in.MarshalToString()
 	// 构建请求的URI。
@@ -81,114 +114,92 @@ func (h openai) Completions(opt client.Options, ctx context.Context, in request.
 	// 设置请求的上下文。
 	req = req.WithContext(ctx)
 	// 发送HTTP请求并获取响应。
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := opt.HTTP().Do(req)
 	if err != nil {
-		return nil, errorx.InvalidRequest
-	}
-
-	// 创建一个用于接收响应的通道。
-	out := make(chan response.Response, in.ChannelMaxLength)
-	go func() {
-		select {
-		case <-ctx.Done():
-			resp.Body.Close()
-			close(out)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-	}()
+		return nil, errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
 
-	// 如果响应状态码不是200，则认为请求失败。
+	// 如果响应状态码不是200，则将响应体解析为 APIError 返回。
 	if resp.StatusCode != http.StatusOK {
-		// 尝试读取响应体的内容。
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-
-		// 记录请求失败的详细信息。
-		slog.Error("openai Completions error", slog.String("uri", uri), slog.String("payload", payload), slog.String("response", string(body)))
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		slog.Error(h.compatible.Provider+" Completions error", slog.String("uri", uri), slog.String("payload", payload), slog.String("response", string(body)))
+		err = h.compatible.APIError(resp.StatusCode, body)
 		return nil, err
 	}
 
-	go func() {
-		var err error
-		defer func() {
-			if err != nil {
-				slog.Error("openai Completions error", slog.String("uri", uri), slog.Any("err", err))
-			}
-		}()
-
+	// 由 stream.Start 负责通道与响应体的生命周期。
+	out := stream.Start(ctx, cancel, resp.Body, in.ChannelMaxLength, h.compatible.Provider, func(send stream.Send) error {
 		if in.Stream {
-			err = h.handlerStream(resp.Body, out, cancel)
-		} else {
-			err = h.handlerResponse(resp.Body, out, cancel)
+			opts := stream.NewOptions(h.compatible.Provider, opt)
+			opts.APIError = h.compatible.APIError
+			return h.handlerStream(resp.Body, opts, restoreModel(model, in.Model, send))
 		}
-	}()
+		return h.handlerResponse(resp.Body, restoreModel(model, in.Model, send))
+	})
 	return out, nil
 }
 
 // handlerResponse 处理OpenAI的响应。
-// 它使用一个io.ReadCloser来解码响应体，并将解码后的响应发送到指定的通道。
-// 函数返回一个响应通道和可能的错误。
+// 它使用一个io.ReadCloser来解码响应体，并将解码后的响应通过 send 输出。
 // 参数:
 //
 //	reader io.ReadCloser - 用于读取和关闭响应体的接口。
-//	out chan response.Response - 用于发送解码后的响应的通道。
+//	send stream.Send - 将解码后的响应输出给调用方的函数。
 //
 // 返回值:
 //
 //	error - 解码过程中可能出现的错误。
-func (openai) handlerResponse(reader io.ReadCloser, out chan response.Response, cancel context.CancelFunc) error {
-	defer cancel()
+func (openai) handlerResponse(reader io.ReadCloser, send stream.Send) error {
 	var resp response.Response
 	if err := sonic.ConfigDefault.NewDecoder(reader).Decode(&resp); err != nil {
-		slog.Error("openai Completions decode error", slog.String("error", err.Error()))
-		return err
+		return errors.Wrap(errorx.InvalidOutput, err.Error())
 	}
 
-	out <- resp
+	send(resp)
 	return nil
 }
 
-// handlerResponse 处理响应的函数。
-// 该函数负责从reader中读取响应数据，并通过out通道发送处理结果。
-// cancel函数用于在需要时取消处理过程。
+// handlerStream 处理OpenAI的流式响应。
+// 该函数负责从reader中读取流数据，并通过 send 输出处理结果。
 // 参数:
 //
-//	reader: 一个io.ReadCloser接口，用于读取响应数据。
-//	out: 一个response.Response类型的通道，用于发送处理结果。
-//	cancel: 一个函数，调用该函数可以取消处理过程。
+//	reader: 一个io.ReadCloser接口，用于读取流数据。
+//	opts: 流读取器的选项。
+//	send: 将处理结果输出给调用方的函数。
 //
 // 返回值:
 //
-//	error - 解码过程中可能出现的错误。
-func (openai) handlerStream(reader io.ReadCloser, out chan response.Response, cancel context.CancelFunc) error {
-	defer cancel()
-
-	code := decoder.New(reader)
-	for {
-		event, err := code.Decode()
-		if err != nil {
-			if err != io.EOF {
-				slog.Error("openai Completions stream error", slog.String("error", err.Error()))
-				return err
-			}
-			break
-		}
-
-		if event.Data == "[DONE]" {
-			break
-		}
+//	error - 读取过程中出现的错误，如格式错误的分片、错误事件、空闲超时或被截断的流。
+func (openai) handlerStream(reader io.ReadCloser, opts stream.Options, send stream.Send) error {
+	return stream.Read(reader, opts, decodeChunk, send)
+}
 
-		var resp response.Response
-		if err := sonic.ConfigDefault.UnmarshalFromString(event.Data, &resp); err != nil {
-			panic(err)
-		}
+// decodeChunk 解码 OpenAI 格式的流式分片
+func decodeChunk(data string) (response.Response, error) {
+	var resp response.Response
+	err := sonic.ConfigDefault.UnmarshalFromString(data, &resp)
+	return resp, err
+}
 
-		out <- resp
-		if len(resp.Choices) > 0 && strings.ToLower(resp.Choices[0].FinishReason) == "stop" {
-			break
-		}
+// MapModel 返回模型名称在映射中对应的模型标识，未配置映射的模型名称原样返回。
+func MapModel(mapping map[string]string, model string) string {
+	if id, ok := mapping[model]; ok && id != "" {
+		return id
 	}
+	return model
+}
 
-	return nil
+// restoreModel 在模型名称被映射时，将响应中的模型名称还原为调用方使用的名称
+func restoreModel(model, mapped string, send stream.Send) stream.Send {
+	if model == mapped {
+		return send
+	}
+	return func(resp response.Response) bool {
+		resp.Model = model
+		return send(resp)
+	}
 }
@@ -0,0 +1,55 @@
+package openai
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Embeddings 方法用于获取文本的向量。
+// 兼容 OpenAI /v1/embeddings 接口的服务（如通义千问兼容模式、Ollama）都可以使用该方法，模型名称同样按映射替换。
+func (h openai) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+
+	// 定义默认的API端点
+	endpoint := "/v1/embeddings"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	model := in.Model
+	in.Model = MapModel(opt.ModelMapping, in.Model)
+
+	var resp response.EmbeddingResponse
+	if err := httpx.PostJSON(ctx, opt.HTTP(), h.compatible.Provider, opt.Host+endpoint, opt.Header, in, &resp); err != nil {
+		if apiErr, ok := err.(*errorx.APIError); ok {
+			return nil, h.compatible.APIError(apiErr.StatusCode, []byte(apiErr.Body))
+		}
+		return nil, err
+	}
+	if model != in.Model {
+		resp.Model = model
+	}
+	return &resp, nil
+}
+
+// EmbeddingBatchSize 返回指定模型单次请求允许的最大文本条数。
+// OpenAI 单次最多 2048 条；通义千问的 text-embedding-v1/v2 最多 25 条，v3 及以上最多 10 条。
+func (openai) EmbeddingBatchSize(model string) int {
+	switch {
+	case strings.HasPrefix(model, "text-embedding-v1"), strings.HasPrefix(model, "text-embedding-v2"):
+		return 25
+	case strings.HasPrefix(model, "text-embedding-v"):
+		return 10
+	default:
+		return 2048
+	}
+}
@@ -0,0 +1,33 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Images 方法用于根据描述生成图像，对应 OpenAI 的 /v1/images/generations 接口。
+// OpenAI 不支持反向提示词，NegativePrompt 会被忽略。
+func (openai) Images(opt client.Options, ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+
+	// 定义默认的API端点
+	endpoint := "/v1/images/generations"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+	in.NegativePrompt = ""
+
+	var resp response.ImageResponse
+	if err := httpx.PostJSON(ctx, opt.HTTP(), client.OpenAI, opt.Host+endpoint, opt.Header, in, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,229 @@
+// Package stream 提供各适配器共用的 SSE 流读取器。
+// 它负责解析事件、识别 [DONE] 结束标记与服务商的错误事件、检测空闲超时与被截断的流，
+// 并按策略处理无法解析的分片，使各适配器只需关心单个分片的格式转换。
+package stream
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/response"
+)
+
+// Done 是 OpenAI 兼容接口的流结束标记
+const Done = "[DONE]"
+
+// Event 是一个 SSE 事件
+type Event struct {
+	Name string // 事件名称，即 event 字段，未设置时为空
+	Data string // 事件数据，多行 data 字段以换行连接
+}
+
+// Options 结构体定义了流读取器的选项
+type Options struct {
+	Provider      string                                             // 服务商类型，用于错误信息与日志
+	IdleTimeout   time.Duration                                      // 两个事件之间的最长等待时间，0 表示不限制
+	SkipMalformed bool                                               // 是否跳过无法解析的分片
+	APIError      func(statusCode int, body []byte) *errorx.APIError // 将错误事件转换为 APIError，默认使用 errorx.NewAPIError
+}
+
+// NewOptions 根据客户端选项创建流读取器的选项
+func NewOptions(provider string, opt client.Options) Options {
+	return Options{Provider: provider, IdleTimeout: opt.StreamIdleTimeout, SkipMalformed: opt.SkipMalformedChunks}
+}
+
+// Send 将一个响应输出给调用方，调用方已放弃读取时返回 false，此时应停止读取并尽快返回
+type Send func(response.Response) bool
+
+// Start 在新的协程中运行 produce，并返回其输出的通道。
+// 该协程是通道与 body 唯一的所有者：produce 返回后依次关闭 body、通道并调用 cancel；
+// ctx 结束时 body 会被立即关闭以打断阻塞的读取，send 在 ctx 结束后不再阻塞，
+// 因此调用方取消 ctx 后不会遗留协程与连接。produce 返回的错误在 ctx 未结束时作为最后一个响应的 Err 输出。
+func Start(ctx context.Context, cancel context.CancelFunc, body io.ReadCloser, size int, provider string, produce func(send Send) error) chan response.Response {
+	out := make(chan response.Response, size)
+	send := func(resp response.Response) bool {
+		select {
+		case out <- resp:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		body.Close()
+	}()
+
+	go func() {
+		defer cancel()
+		defer close(out)
+		defer body.Close()
+
+		err := produce(send)
+		// 调用方取消时不再输出错误。
+		if err != nil && ctx.Err() == nil {
+			slog.Error(provider+" Completions error", slog.Any("err", err))
+			send(response.Response{Err: err})
+		}
+	}()
+	return out
+}
+
+// Decode 将一个事件的数据转换为统一的响应结构。
+// 返回 *errorx.APIError 表示服务商在分片中返回了错误，其余错误视为分片格式错误。
+type Decode func(data string) (response.Response, error)
+
+// Read 读取 body 中的事件，使用 decode 转换后依次交给 send，直到流结束、调用方放弃读取或出现错误。
+// 流以 [DONE] 结束，或在没有 [DONE] 的情况下以携带结束原因的分片结束时返回 nil；
+// 流在此之前结束时返回 errorx.StreamTruncated，超过空闲时间没有收到事件时关闭 body 并返回 errorx.StreamTimeout。
+func Read(body io.ReadCloser, opts Options, decode Decode, send Send) error {
+	r := newReader(body, opts)
+	defer r.stop()
+
+	finished := false
+	for {
+		event, err := r.next()
+		if err == io.EOF {
+			if finished {
+				return nil
+			}
+			return errors.Wrapf(errorx.StreamTruncated, "%s stream ended without finish reason", opts.Provider)
+		}
+		if err != nil {
+			return err
+		}
+
+		if event.Data == Done {
+			return nil
+		}
+		if event.Data == "" {
+			continue
+		}
+		if event.Name == "error" || strings.HasPrefix(event.Data, `{"error"`) {
+			return r.apiError(event.Data)
+		}
+
+		resp, err := decode(event.Data)
+		if err != nil {
+			var apiErr *errorx.APIError
+			if errors.As(err, &apiErr) {
+				return apiErr
+			}
+			chunkErr := &errorx.ChunkError{Provider: opts.Provider, Data: event.Data, Err: err}
+			if !opts.SkipMalformed {
+				return chunkErr
+			}
+			slog.Warn("skip malformed chunk", slog.String("provider", opts.Provider), slog.Any("err", chunkErr))
+			continue
+		}
+
+		for _, c := range resp.Choices {
+			if c.FinishReason != "" {
+				finished = true
+			}
+		}
+		if !send(resp) {
+			return nil
+		}
+	}
+}
+
+// reader 逐个读取 SSE 事件，并在空闲超时后关闭 body
+type reader struct {
+	body     io.ReadCloser
+	buf      *bufio.Reader
+	opts     Options
+	timer    *time.Timer
+	timedOut atomic.Bool
+}
+
+// newReader 创建一个 reader，设置了空闲时间时创建计时器，计时只在读取事件期间进行
+func newReader(body io.ReadCloser, opts Options) *reader {
+	r := &reader{body: body, buf: bufio.NewReader(body), opts: opts}
+	if opts.IdleTimeout > 0 {
+		r.timer = time.AfterFunc(opts.IdleTimeout, func() {
+			r.timedOut.Store(true)
+			body.Close()
+		})
+		r.timer.Stop()
+	}
+	return r
+}
+
+// stop 停止空闲计时
+func (r *reader) stop() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
+// next 读取下一个事件，流结束时返回 io.EOF。
+// 与 SSE 规范不同，流结束时尚未以空行结尾的事件也会被返回，以兼容省略最后一个空行的服务商。
+// 空闲计时在读取前开始、返回前停止，调用方转换与输出事件的耗时不计入空闲时间。
+func (r *reader) next() (Event, error) {
+	if r.timer != nil {
+		r.timer.Reset(r.opts.IdleTimeout)
+		defer r.timer.Stop()
+	}
+
+	var event Event
+	var data strings.Builder
+	seen := false
+
+	for {
+		line, err := r.buf.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if r.timedOut.Load() {
+				return Event{}, errors.Wrapf(errorx.StreamTimeout, "%s stream idle for %s", r.opts.Provider, r.opts.IdleTimeout)
+			}
+			if err == io.EOF && seen {
+				event.Data = data.String()
+				return event, nil
+			}
+			return Event{}, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if !seen {
+				continue
+			}
+			event.Data = data.String()
+			return event, nil
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			// 以冒号开头的行是注释，常用于保持连接
+		case "event":
+			event.Name, seen = value, true
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			seen = true
+		}
+	}
+}
+
+// apiError 将错误事件转换为 APIError
+func (r *reader) apiError(data string) *errorx.APIError {
+	if r.opts.APIError != nil {
+		return r.opts.APIError(http.StatusOK, []byte(data))
+	}
+	return errorx.NewAPIError(r.opts.Provider, http.StatusOK, []byte(data))
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/response"
+)
+
+func decode(data string) (response.Response, error) {
+	var resp response.Response
+	err := sonic.ConfigDefault.UnmarshalFromString(data, &resp)
+	return resp, err
+}
+
+func read(body string, opts Options) (string, error) {
+	var content strings.Builder
+	err := Read(io.NopCloser(strings.NewReader(body)), opts, decode, func(resp response.Response) bool {
+		content.WriteString(resp.Choices[0].Delta.Content)
+		return true
+	})
+	return content.String(), err
+}
+
+func Test_Read(t *testing.T) {
+	// 注释行、CRLF 换行与多行 data 字段，且最后一个事件没有以空行结尾
+	body := ": keep-alive\r\n\r\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"你\"}}]}\r\n\r\n" +
+		"data: {\"choices\":[{\"delta\":\ndata: {\"content\":\"好\"},\"finish_reason\":\"stop\"}]}\n"
+	if got, err := read(body, Options{Provider: "test"}); err != nil || got != "你好" {
+		t.Fatalf("Read = %q, %v", got, err)
+	}
+
+	_, err := read("data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n", Options{Provider: "test"})
+	if !errors.Is(err, errorx.StreamTruncated) {
+		t.Fatalf("err = %v, want StreamTruncated", err)
+	}
+
+	malformed := "data: {oops\n\ndata: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\ndata: [DONE]\n\n"
+	if _, err := read(malformed, Options{Provider: "test"}); !errors.Is(err, errorx.InvalidOutput) {
+		t.Fatalf("err = %v, want InvalidOutput", err)
+	}
+	if got, err := read(malformed, Options{Provider: "test", SkipMalformed: true}); err != nil || got != "a" {
+		t.Fatalf("skip policy: %q, %v", got, err)
+	}
+
+	var apiErr *errorx.APIError
+	_, err = read("event: error\ndata: {\"error\":{\"code\":\"overloaded\",\"message\":\"busy\"}}\n\n", Options{Provider: "test"})
+	if !errors.As(err, &apiErr) || apiErr.Code != "overloaded" {
+		t.Fatalf("err = %v, want APIError", err)
+	}
+}
+
+// closer 在关闭后读取失败，用于模拟空闲超时关闭连接
+type closer struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (c *closer) Read(p []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+	return c.Reader.Read(p)
+}
+
+func (c *closer) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func Test_ReadIdleTimeout(t *testing.T) {
+	// 调用方处理分片的耗时不计入空闲时间
+	chunk := "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n"
+	body := &closer{Reader: iotest.OneByteReader(strings.NewReader(strings.Repeat(chunk, 3) + "data: [DONE]\n\n"))}
+	opts := Options{Provider: "test", IdleTimeout: 50 * time.Millisecond}
+	var content strings.Builder
+	err := Read(body, opts, decode, func(resp response.Response) bool {
+		time.Sleep(100 * time.Millisecond)
+		content.WriteString(resp.Choices[0].Delta.Content)
+		return true
+	})
+	if err != nil || content.String() != "aaa" {
+		t.Fatalf("slow consumer: %q, %v", content.String(), err)
+	}
+
+	// 服务商长时间没有发送事件时超时
+	r, w := io.Pipe()
+	defer w.Close()
+	err = Read(r, opts, decode, func(response.Response) bool { return true })
+	if !errors.Is(err, errorx.StreamTimeout) {
+		t.Fatalf("err = %v, want StreamTimeout", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package tc3 实现了腾讯云 API 3.0 的 TC3-HMAC-SHA256 签名算法，
+// 可供混元等所有基于腾讯云 API 3.0 的服务复用。
+package tc3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm 是签名算法名称
+const Algorithm = "TC3-HMAC-SHA256"
+
+// Signer 结构体保存了签名所需的凭证与服务名。
+type Signer struct {
+	SecretID  string // 腾讯云 SecretId
+	SecretKey string // 腾讯云 SecretKey
+	Service   string // 服务名，如 hunyuan、cvm，通常为域名的第一段
+}
+
+// NewSigner 创建一个新的 Signer 实例。
+func NewSigner(secretID, secretKey, service string) *Signer {
+	return &Signer{SecretID: secretID, SecretKey: secretKey, Service: service}
+}
+
+// Sign 为请求计算签名，并设置 X-TC-Timestamp 与 Authorization 请求头。
+// 参与签名的请求头为 content-type 与 host，body 需与实际发送的请求体一致。
+func (s *Signer) Sign(req *http.Request, body []byte, now time.Time) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         host,
+	}
+
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("Authorization", s.Authorization(req.Method, req.URL.EscapedPath(), req.URL.RawQuery, headers, body, now))
+}
+
+// Authorization 根据请求的各个部分计算 Authorization 请求头的值。
+func (s *Signer) Authorization(method, path, query string, headers map[string]string, body []byte, now time.Time) string {
+	canonical, signedHeaders := CanonicalRequest(method, path, query, headers, body)
+	date := now.UTC().Format("2006-01-02")
+	scope := date + "/" + s.Service + "/tc3_request"
+	signature := s.Signature(StringToSign(canonical, scope, now), date)
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", Algorithm, s.SecretID, scope, signedHeaders, signature)
+}
+
+// Signature 使用派生密钥对待签名字符串计算签名。
+// 派生密钥依次以日期、服务名与 tc3_request 对 "TC3"+SecretKey 做 HMAC-SHA256 得到。
+func (s *Signer) Signature(stringToSign, date string) string {
+	secretDate := hmacSHA256([]byte("TC3"+s.SecretKey), date)
+	secretService := hmacSHA256(secretDate, s.Service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	return hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+}
+
+// CanonicalRequest 拼接规范请求串，返回规范请求串与参与签名的请求头列表。
+// 请求头的名称与值都会被转为小写并去掉首尾空格，按名称的字典序排列。
+func CanonicalRequest(method, path, query string, headers map[string]string, body []byte) (string, string) {
+	if path == "" {
+		path = "/"
+	}
+
+	names := make([]string, 0, len(headers))
+	values := make(map[string]string, len(headers))
+	for k, v := range headers {
+		name := strings.ToLower(strings.TrimSpace(k))
+		names = append(names, name)
+		values[name] = strings.ToLower(strings.TrimSpace(v))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		query,
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+	return canonical, signedHeaders
+}
+
+// StringToSign 拼接待签名字符串。
+func StringToSign(canonicalRequest, credentialScope string, now time.Time) string {
+	return strings.Join([]string{
+		Algorithm,
+		strconv.FormatInt(now.Unix(), 10),
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+}
+
+// sha256Hex 计算数据的 SHA256 并以小写十六进制返回
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 使用 key 对 msg 计算 HMAC-SHA256
+func hmacSHA256(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,59 @@
+package tc3
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// 以下数据取自腾讯云 API 3.0 签名方法 v3 文档中的示例。
+const (
+	testSecretID  = "AKIDz8krbsJ5yKBZQpn74WFkmLPx3*******"
+	testSecretKey = "Gu5t9xGARNpq86cd98joQYCN3*******"
+	testPayload   = `{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`
+)
+
+var testTime = time.Unix(1551113065, 0)
+
+func Test_CanonicalRequest(t *testing.T) {
+	canonical, signedHeaders := CanonicalRequest(http.MethodPost, "/", "", map[string]string{
+		"Content-Type": "application/json; charset=utf-8",
+		"Host":         "cvm.tencentcloudapi.com",
+	}, []byte(testPayload))
+
+	want := "POST\n/\n\ncontent-type:application/json; charset=utf-8\nhost:cvm.tencentcloudapi.com\n\ncontent-type;host\n" +
+		"35e9c5b0e3ae67532d3c9f17ead6c90222632e5b1ff7f6e89887f1398934f064"
+	if canonical != want {
+		t.Fatalf("canonical request mismatch:\n%s\nwant:\n%s", canonical, want)
+	}
+	if signedHeaders != "content-type;host" {
+		t.Fatalf("signed headers = %s", signedHeaders)
+	}
+
+	stringToSign := StringToSign(canonical, "2019-02-25/cvm/tc3_request", testTime)
+	wantStringToSign := "TC3-HMAC-SHA256\n1551113065\n2019-02-25/cvm/tc3_request\n" +
+		"5ffe6a04c0664d6b969fab9a13bdab201d63ee709638e2749d62a09ca18d7031"
+	if stringToSign != wantStringToSign {
+		t.Fatalf("string to sign mismatch:\n%s\nwant:\n%s", stringToSign, wantStringToSign)
+	}
+}
+
+func Test_Sign(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://cvm.tencentcloudapi.com", bytes.NewReader([]byte(testPayload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	NewSigner(testSecretID, testSecretKey, "cvm").Sign(req, []byte(testPayload), testTime)
+
+	want := "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3*******/2019-02-25/cvm/tc3_request, " +
+		"SignedHeaders=content-type;host, Signature=2230eefd229f582d8b1b891af7107b91597240707d778ab3738f756258d7652c"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("authorization mismatch:\n%s\nwant:\n%s", got, want)
+	}
+	if got := req.Header.Get("X-TC-Timestamp"); got != "1551113065" {
+		t.Fatalf("timestamp = %s", got)
+	}
+}
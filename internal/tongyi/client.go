@@ -0,0 +1,59 @@
+package tongyi
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/internal/openai"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	// compatibleChatEndpoint 是 DashScope 兼容 OpenAI 格式的对话接口
+	compatibleChatEndpoint = "/compatible-mode/v1/chat/completions"
+	// compatibleEmbeddingEndpoint 是 DashScope 兼容 OpenAI 格式的向量接口
+	compatibleEmbeddingEndpoint = "/compatible-mode/v1/embeddings"
+)
+
+// tongyi 结构体实现了 client.IClient 接口，用于与阿里云百炼（DashScope）服务进行交互。
+// 对话与向量接口使用 DashScope 的 OpenAI 兼容模式，图像生成使用通义万相的原生异步接口。
+type tongyi struct {
+	compatible client.IClient // OpenAI 兼容模式的客户端
+}
+
+// NewClient 创建并返回一个 tongyi 实例，该实例实现了 client.IClient 接口。
+// Host 应设置为 https://dashscope.aliyuncs.com，不需要包含 /compatible-mode 路径。
+func NewClient() client.IClient {
+	return &tongyi{compatible: openai.NewClient()}
+}
+
+// SupportsResponseFormat 返回是否原生支持指定的输出格式。
+// 通义千问的兼容模式仅支持 json_object 输出格式。
+func (tongyi) SupportsResponseFormat(formatType string) bool {
+	return formatType == request.ResponseFormatJSONObject
+}
+
+// Completions 方法用于获取补全建议，请求会被转发到 DashScope 的 OpenAI 兼容模式。
+func (h tongyi) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = compatibleChatEndpoint
+	}
+	return h.compatible.Completions(opt, ctx, in)
+}
+
+// Embeddings 方法用于获取文本的向量，请求会被转发到 DashScope 的 OpenAI 兼容模式。
+func (h tongyi) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = compatibleEmbeddingEndpoint
+	}
+	return h.compatible.Embeddings(opt, ctx, in)
+}
+
+// EmbeddingBatchSize 返回指定模型单次请求允许的最大文本条数。
+func (h tongyi) EmbeddingBatchSize(model string) int {
+	if sizer, ok := h.compatible.(client.EmbeddingBatchSizer); ok {
+		return sizer.EmbeddingBatchSize(model)
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+package tongyi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/poll"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	// imageSynthesisEndpoint 是通义万相文生图的异步接口
+	imageSynthesisEndpoint = "/api/v1/services/aigc/text2image/image-synthesis"
+	// taskEndpoint 是 DashScope 异步任务的查询接口
+	taskEndpoint = "/api/v1/tasks/"
+)
+
+// 以下为 DashScope 异步任务的状态
+const (
+	taskSucceeded = "SUCCEEDED"
+	taskFailed    = "FAILED"
+	taskCanceled  = "CANCELED"
+	taskUnknown   = "UNKNOWN"
+)
+
+// pollOptions 是查询异步任务状态时使用的轮询选项
+var pollOptions = []poll.Option{poll.WithInterval(time.Second), poll.WithMaxInterval(5 * time.Second)}
+
+// imageRequest 是通义万相文生图接口的请求结构
+type imageRequest struct {
+	Model      string          `json:"model"`
+	Input      imageInput      `json:"input"`
+	Parameters imageParameters `json:"parameters"`
+}
+
+// imageInput 是通义万相的输入参数
+type imageInput struct {
+	Prompt         string `json:"prompt"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+}
+
+// imageParameters 是通义万相的生成参数
+type imageParameters struct {
+	Style string `json:"style,omitempty"` // 图像风格，如 <auto>、<watercolor>
+	Size  string `json:"size,omitempty"`  // 图像尺寸，格式为 1024*1024
+	N     int    `json:"n,omitempty"`     // 生成的图像数量
+	Seed  *int   `json:"seed,omitempty"`  // 随机种子
+}
+
+// task 是 DashScope 异步任务的响应结构
+type task struct {
+	RequestID string `json:"request_id"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Output    struct {
+		TaskID     string `json:"task_id"`
+		TaskStatus string `json:"task_status"`
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+		Results    []struct {
+			URL     string `json:"url"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"results"`
+	} `json:"output"`
+}
+
+// Images 方法用于根据描述生成图像。
+// 通义万相的文生图接口是异步的：先提交任务获取任务 ID，再按指数退避轮询任务状态，
+// 直到任务成功、失败或 ctx 被取消。
+func (h tongyi) Images(opt client.Options, ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+
+	endpoint := imageSynthesisEndpoint
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	body := imageRequest{
+		Model: in.Model,
+		Input: imageInput{Prompt: in.Prompt, NegativePrompt: in.NegativePrompt},
+		Parameters: imageParameters{
+			Style: in.Style,
+			Size:  strings.ReplaceAll(in.Size, "x", "*"),
+			N:     in.N,
+			Seed:  in.Seed,
+		},
+	}
+
+	header := opt.Header.Clone()
+	header.Set("X-DashScope-Async", "enable")
+
+	var submitted task
+	if err := httpx.PostJSON(ctx, opt.HTTP(), client.Tongyi, opt.Host+endpoint, header, body, &submitted); err != nil {
+		return nil, err
+	}
+
+	taskID := submitted.Output.TaskID
+	if taskID == "" {
+		return nil, &errorx.APIError{Provider: client.Tongyi, StatusCode: http.StatusOK, Code: submitted.Code, Message: submitted.Message, RequestID: submitted.RequestID}
+	}
+
+	result, err := poll.Until(ctx, func(ctx context.Context) (task, bool, error) {
+		return h.queryTask(ctx, opt, taskID)
+	}, pollOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.ImageResponse{TaskID: taskID}
+	for _, item := range result.Output.Results {
+		if item.URL != "" {
+			resp.Data = append(resp.Data, response.Image{URL: item.URL})
+		}
+	}
+	return resp, nil
+}
+
+// queryTask 查询异步任务的状态，任务失败时返回 APIError
+func (tongyi) queryTask(ctx context.Context, opt client.Options, taskID string) (task, bool, error) {
+	var t task
+	req, err := httpx.NewRequest(ctx, http.MethodGet, opt.Host+taskEndpoint+url.PathEscape(taskID), opt.Header, nil)
+	if err != nil {
+		return t, false, err
+	}
+	if err := httpx.Do(opt.HTTP(), req, client.Tongyi, &t); err != nil {
+		return t, false, err
+	}
+
+	switch t.Output.TaskStatus {
+	case taskSucceeded:
+		return t, true, nil
+	case taskFailed, taskCanceled, taskUnknown:
+		return t, false, &errorx.APIError{
+			Provider:   client.Tongyi,
+			StatusCode: http.StatusOK,
+			Code:       t.Output.Code,
+			Message:    t.Output.Message,
+			RequestID:  t.RequestID,
+			Err:        errorx.ServerError,
+		}
+	default:
+		return t, false, nil
+	}
+}
@@ -0,0 +1,64 @@
+package tongyi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/poll"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_Images(t *testing.T) {
+	pollOptions = []poll.Option{poll.WithInterval(time.Millisecond)}
+
+	var queries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == imageSynthesisEndpoint:
+			if r.Header.Get("X-DashScope-Async") != "enable" {
+				t.Error("missing X-DashScope-Async header")
+			}
+			var in imageRequest
+			if err := sonic.ConfigDefault.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Error(err)
+				return
+			}
+			if in.Input.Prompt != "a cat" || in.Parameters.Size != "1024*1024" || in.Parameters.N != 2 {
+				t.Errorf("unexpected request: %+v", in)
+			}
+			fmt.Fprint(w, `{"request_id":"r1","output":{"task_id":"t1","task_status":"PENDING"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == taskEndpoint+"t1":
+			queries++
+			if queries < 3 {
+				fmt.Fprint(w, `{"request_id":"r2","output":{"task_id":"t1","task_status":"RUNNING"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"request_id":"r3","output":{"task_id":"t1","task_status":"SUCCEEDED","results":[{"url":"https://a/1.png"},{"url":"https://a/2.png"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opt := client.NewOptions(client.WithHost(srv.URL))
+	resp, err := NewClient().(client.ImageGenerator).Images(*opt, context.Background(), *request.NewImageRequest(
+		request.WithImageModel("wanx-v1"),
+		request.WithPrompt("a cat"),
+		request.WithSize("1024x1024"),
+		request.WithN(2),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queries != 3 || resp.TaskID != "t1" || len(resp.Data) != 2 || resp.Data[1].URL != "https://a/2.png" {
+		t.Fatalf("unexpected response after %d queries: %+v", queries, resp)
+	}
+}
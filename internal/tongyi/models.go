@@ -0,0 +1,30 @@
+package tongyi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/response"
+)
+
+// ListModels 方法用于获取百炼提供的模型列表，对应兼容模式的 /compatible-mode/v1/models 接口。
+func (tongyi) ListModels(opt client.Options, ctx context.Context) (*response.ModelList, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+
+	req, err := httpx.NewRequest(ctx, http.MethodGet, opt.Host+"/compatible-mode/v1/models", opt.Header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp response.ModelList
+	if err := httpx.Do(opt.HTTP(), req, client.Tongyi, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -5,13 +5,14 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 
-	"github.com/alevinval/sse/pkg/decoder"
 	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
 
 	"github.com/jun3372/uniai/client"
 	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/stream"
 	"github.com/jun3372/uniai/request"
 	"github.com/jun3372/uniai/response"
 )
@@ -75,115 +76,96 @@ func (h xfyun) Completions(opt client.Options, ctx context.Context, in request.R
 	// 设置请求的上下文。
 	req = req.WithContext(ctx)
 	// 发送HTTP请求并获取响应。
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := opt.HTTP().Do(req)
 	if err != nil {
-		return nil, errorx.InvalidRequest
-	}
-
-	// 创建一个用于接收响应的通道。
-	out := make(chan response.Response, in.ChannelMaxLength)
-	go func() {
-		select {
-		case <-ctx.Done():
-			resp.Body.Close()
-			close(out)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-	}()
+		return nil, errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
 
-	// 如果响应状态码不是200，则认为请求失败。
+	// 如果响应状态码不是200，则将响应体解析为 APIError 返回。
 	if resp.StatusCode != http.StatusOK {
-		// 尝试读取响应体的内容。
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-
-		// 记录请求失败的详细信息。
-		slog.Error("openai Completions error", slog.String("uri", uri), slog.String("payload", payload), slog.String("response", string(body)))
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		slog.Error("xfyun Completions error", slog.String("uri", uri), slog.String("payload", payload), slog.String("response", string(body)))
+		err = errorx.NewAPIError(client.Xfyun, resp.StatusCode, body)
 		return nil, err
 	}
 
-	go func() {
-		var err error
-		defer func() {
-			if err != nil {
-				slog.Error("openai Completions error", slog.String("uri", uri), slog.Any("err", err))
-			}
-		}()
-
+	// 由 stream.Start 负责通道与响应体的生命周期。
+	out := stream.Start(ctx, cancel, resp.Body, in.ChannelMaxLength, client.Xfyun, func(send stream.Send) error {
 		if in.Stream {
-			err = h.handlerStream(resp.Body, out, cancel)
-		} else {
-			err = h.handlerResponse(resp.Body, out, cancel)
+			return h.handlerStream(resp.Body, stream.NewOptions(client.Xfyun, opt), send)
 		}
-	}()
+		return h.handlerResponse(resp.Body, send)
+	})
 	return out, nil
 }
 
 // handlerStream 处理流的函数。
-// 该函数负责从reader中读取流数据，并通过out通道发送处理结果。
-// cancel函数用于在需要时取消处理过程。
+// 该函数负责从reader中读取流数据，并通过 send 输出处理结果。
 // 参数:
 //
 //	reader: 一个io.ReadCloser接口，用于读取流数据。
-//	out: 一个response.Response类型的通道，用于发送处理结果。
-//	cancel: 一个函数，调用该函数可以取消处理过程。
+//	opts: 流读取器的选项。
+//	send: 将处理结果输出给调用方的函数。
 //
 // 返回值:
 //
-//	error - 解码过程中可能出现的错误。
-func (xfyun) handlerStream(reader io.ReadCloser, out chan response.Response, cancel context.CancelFunc) error {
-	defer cancel()
-
-	code := decoder.New(reader)
-	for {
-		event, err := code.Decode()
-		if err != nil {
-			if err != io.EOF {
-				slog.Error("openai Completions stream error", slog.String("error", err.Error()))
-				return err
-			}
-			break
-		}
-
-		if event.Data == "[DONE]" {
-			break
-		}
-
-		var resp response.Response
-		if err := sonic.ConfigDefault.UnmarshalFromString(event.Data, &resp); err != nil {
-			panic(err)
-		}
-
-		out <- resp
-		if len(resp.Choices) > 0 && strings.ToLower(resp.Choices[0].FinishReason) == "stop" {
-			break
-		}
-	}
-
-	return nil
+//	error - 读取过程中出现的错误，如格式错误的分片、错误分片、空闲超时或被截断的流。
+func (xfyun) handlerStream(reader io.ReadCloser, opts stream.Options, send stream.Send) error {
+	return stream.Read(reader, opts, decodeChunk, send)
 }
 
 // handlerResponse 处理响应的函数。
-// 该函数负责从reader中读取响应数据，并通过out通道发送处理结果。
-// cancel函数用于在需要时取消处理过程。
+// 该函数负责从reader中读取响应数据，并通过 send 输出处理结果。
 // 参数:
 //
 //	reader: 一个io.ReadCloser接口，用于读取响应数据。
-//	out: 一个response.Response类型的通道，用于发送处理结果。
-//	cancel: 一个函数，调用该函数可以取消处理过程。
+//	send: 将处理结果输出给调用方的函数。
 //
 // 返回值:
 //
 //	error - 解码过程中可能出现的错误。
-func (xfyun) handlerResponse(reader io.ReadCloser, out chan response.Response, cancel context.CancelFunc) error {
-	defer cancel()
-	var resp response.Response
-	if err := sonic.ConfigDefault.NewDecoder(reader).Decode(&resp); err != nil {
-		slog.Error("openai Completions decode error", slog.String("error", err.Error()))
+func (xfyun) handlerResponse(reader io.ReadCloser, send stream.Send) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
 		return err
 	}
 
-	out <- resp
+	resp, err := decodeChunk(string(data))
+	if err != nil {
+		var apiErr *errorx.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr
+		}
+		return errors.Wrap(errorx.InvalidOutput, err.Error())
+	}
+
+	send(resp)
 	return nil
 }
+
+// chunk 是星火的响应结构，在 OpenAI 格式的基础上携带状态码与会话标识，状态码不为 0 时表示出错。
+type chunk struct {
+	response.Response
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Sid     string `json:"sid"`
+}
+
+// decodeChunk 解码星火的响应，状态码不为 0 时返回 APIError
+func decodeChunk(data string) (response.Response, error) {
+	var c chunk
+	if err := sonic.ConfigDefault.UnmarshalFromString(data, &c); err != nil {
+		return response.Response{}, err
+	}
+	if c.Code != 0 {
+		return response.Response{}, &errorx.APIError{Provider: client.Xfyun, StatusCode: http.StatusOK, Code: strconv.Itoa(c.Code), Message: c.Message, RequestID: c.Sid, Body: data}
+	}
+	if c.RequestID == "" {
+		c.RequestID = c.Sid
+	}
+	return c.Response, nil
+}
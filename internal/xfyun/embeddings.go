@@ -0,0 +1,16 @@
+package xfyun
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Embeddings 方法用于获取文本的向量。
+// 讯飞星火的向量接口不兼容 OpenAI 格式，暂不支持。
+func (xfyun) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	return nil, errorx.NotSupported
+}
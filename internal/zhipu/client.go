@@ -0,0 +1,81 @@
+package zhipu
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/internal/openai"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	// maxTopP 是智谱允许的 top_p 上限，智谱要求 top_p 位于开区间 (0, 1)
+	maxTopP = 0.99
+	// chatEndpoint 是智谱兼容 OpenAI 格式的对话接口
+	chatEndpoint = "/api/paas/v4/chat/completions"
+	// embeddingEndpoint 是智谱兼容 OpenAI 格式的向量接口
+	embeddingEndpoint = "/api/paas/v4/embeddings"
+)
+
+// zhipu 结构体实现了 client.IClient 接口，用于与智谱 GLM 服务进行交互。
+// 智谱的对话与向量接口与 OpenAI 兼容，请求会被转发给 OpenAI 适配器，这里只处理接口地址、鉴权与参数范围的差异。
+type zhipu struct {
+	tokens     *tokenCache    // 按 API Key 缓存的 JWT
+	compatible client.IClient // OpenAI 兼容接口的客户端
+}
+
+// NewClient 创建并返回一个 zhipu 实例，该实例实现了 client.IClient 接口。
+// 调用方通过 Authorization 请求头传入 "id.secret" 格式的 API Key，
+// 客户端会将其签发为短期有效的 JWT 并在过期前自动刷新。
+func NewClient() client.IClient {
+	return &zhipu{tokens: newTokenCache(), compatible: openai.NewCompatibleClient(openai.Compatible{Provider: client.Zhipu})}
+}
+
+// SupportsResponseFormat 返回是否原生支持指定的输出格式。
+// 智谱仅支持 json_object 输出格式，json_schema 需要以提示词模拟。
+func (zhipu) SupportsResponseFormat(formatType string) bool {
+	return formatType == request.ResponseFormatJSONObject
+}
+
+// Completions 方法用于获取补全建议，请求会被转发到智谱兼容 OpenAI 格式的接口。
+// 智谱的流式分片与 OpenAI 格式一致，最后一个分片携带 finish_reason 与 usage，随后以 [DONE] 结束。
+func (h zhipu) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = chatEndpoint
+	}
+
+	// 智谱不接受 top_p >= 1，超出范围时收敛到允许的最大值。
+	if in.TopP != nil && *in.TopP >= 1 {
+		in.TopP = request.Ptr[float32](maxTopP)
+	}
+
+	opt, err := h.sign(opt)
+	if err != nil {
+		return nil, err
+	}
+	return h.compatible.Completions(opt, ctx, in)
+}
+
+// sign 将请求头中的 API Key 签发为 JWT，返回使用 JWT 鉴权的选项
+func (h zhipu) sign(opt client.Options) (client.Options, error) {
+	token, err := h.tokens.Get(apiKey(opt.Header))
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Header = opt.Header.Clone()
+	opt.Header.Set("Authorization", "Bearer "+token)
+	return opt, nil
+}
+
+// apiKey 从请求头中取出调用方传入的 API Key，兼容带 Bearer 前缀的写法。
+func apiKey(header http.Header) string {
+	key := strings.TrimSpace(header.Get("Authorization"))
+	if len(key) > 7 && strings.EqualFold(key[:7], "bearer ") {
+		key = strings.TrimSpace(key[7:])
+	}
+	return key
+}
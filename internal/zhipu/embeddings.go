@@ -0,0 +1,27 @@
+package zhipu
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Embeddings 方法用于获取文本的向量，embedding-3 支持通过 dimensions 指定输出维度。
+func (h zhipu) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	if in.Endpoint == "" {
+		in.Endpoint = embeddingEndpoint
+	}
+
+	opt, err := h.sign(opt)
+	if err != nil {
+		return nil, err
+	}
+	return h.compatible.Embeddings(opt, ctx, in)
+}
+
+// EmbeddingBatchSize 返回单次请求允许的最大文本条数，智谱单次最多 64 条。
+func (zhipu) EmbeddingBatchSize(model string) int {
+	return 64
+}
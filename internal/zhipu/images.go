@@ -0,0 +1,46 @@
+package zhipu
+
+import (
+	"context"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/httpx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// imageRequest 是智谱 CogView 接口的请求结构
+type imageRequest struct {
+	Model  string `json:"model"`             // 模型名称，如 cogview-3-plus
+	Prompt string `json:"prompt"`            // 图像描述
+	Size   string `json:"size,omitempty"`    // 图像尺寸，如 1024x1024
+	UserID string `json:"user_id,omitempty"` // 终端用户的唯一标识
+}
+
+// Images 方法用于根据描述生成图像，对应智谱 CogView 的同步接口。
+// CogView 每次只生成一张图像，且不支持反向提示词与风格参数。
+func (h zhipu) Images(opt client.Options, ctx context.Context, in request.ImageRequest) (*response.ImageResponse, error) {
+	// 检查提供的主机地址是否为空，如果为空则返回错误。
+	if opt.Host == "" {
+		return nil, errorx.InvalidHost
+	}
+
+	// 定义默认的API端点
+	endpoint := "/api/paas/v4/images/generations"
+	if in.Endpoint != "" {
+		endpoint = in.Endpoint
+	}
+
+	opt, err := h.sign(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp response.ImageResponse
+	body := imageRequest{Model: in.Model, Prompt: in.Prompt, Size: in.Size, UserID: in.User}
+	if err := httpx.PostJSON(ctx, opt.HTTP(), client.Zhipu, opt.Host+endpoint, opt.Header, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
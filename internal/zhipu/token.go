@@ -0,0 +1,90 @@
+package zhipu
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+)
+
+const (
+	// tokenTTL 是签发的 JWT 的有效期
+	tokenTTL = 30 * time.Minute
+	// tokenRefreshBefore 表示在 JWT 过期前多久重新签发
+	tokenRefreshBefore = 5 * time.Minute
+)
+
+// cachedToken 表示一个已签发的 JWT 及其过期时间
+type cachedToken struct {
+	value    string
+	expireAt time.Time
+}
+
+// tokenCache 按 API Key 缓存签发好的 JWT，过期前自动重新签发。
+type tokenCache struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	items map[string]cachedToken
+}
+
+// newTokenCache 创建一个空的 JWT 缓存
+func newTokenCache() *tokenCache {
+	return &tokenCache{now: time.Now, items: make(map[string]cachedToken)}
+}
+
+// Get 返回 apiKey 对应的 JWT。
+// 缓存中的 JWT 距离过期不足 tokenRefreshBefore 时会重新签发。
+func (c *tokenCache) Get(apiKey string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if item, ok := c.items[apiKey]; ok && now.Add(tokenRefreshBefore).Before(item.expireAt) {
+		return item.value, nil
+	}
+
+	expireAt := now.Add(tokenTTL)
+	token, err := signToken(apiKey, now, expireAt)
+	if err != nil {
+		return "", err
+	}
+
+	c.items[apiKey] = cachedToken{value: token, expireAt: expireAt}
+	return token, nil
+}
+
+// signToken 将 "id.secret" 格式的 API Key 签发为 HS256 JWT。
+// 智谱要求头部携带 sign_type=SIGN，载荷中的时间戳使用毫秒。
+func signToken(apiKey string, now, expireAt time.Time) (string, error) {
+	id, secret, ok := strings.Cut(apiKey, ".")
+	if !ok || id == "" || secret == "" {
+		return "", errors.Wrap(errorx.InvalidInput, "zhipu api key must be in the form of id.secret")
+	}
+
+	header, err := sonic.ConfigDefault.Marshal(map[string]string{"alg": "HS256", "sign_type": "SIGN"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := sonic.ConfigDefault.Marshal(map[string]any{
+		"api_key":   id,
+		"exp":       expireAt.UnixMilli(),
+		"timestamp": now.UnixMilli(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil)), nil
+}
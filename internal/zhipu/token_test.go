@@ -0,0 +1,78 @@
+package zhipu
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+func Test_SignToken(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	token, err := signToken("my-id.my-secret", now, now.Add(tokenTTL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token should have 3 parts, got %d", len(parts))
+	}
+
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	var h map[string]string
+	if err := sonic.ConfigDefault.Unmarshal(header, &h); err != nil {
+		t.Fatal(err)
+	}
+	if h["alg"] != "HS256" || h["sign_type"] != "SIGN" {
+		t.Fatalf("unexpected header: %s", header)
+	}
+
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var p struct {
+		APIKey    string `json:"api_key"`
+		Exp       int64  `json:"exp"`
+		Timestamp int64  `json:"timestamp"`
+	}
+	if err := sonic.ConfigDefault.Unmarshal(payload, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.APIKey != "my-id" || p.Timestamp != now.UnixMilli() || p.Exp != now.Add(tokenTTL).UnixMilli() {
+		t.Fatalf("unexpected payload: %s", payload)
+	}
+
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if parts[2] != base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) {
+		t.Fatal("signature mismatch")
+	}
+
+	if _, err := signToken("no-secret", now, now); err == nil {
+		t.Fatal("expected error for malformed api key")
+	}
+}
+
+func Test_TokenCache(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	cache := newTokenCache()
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.Get("id.secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(tokenTTL - tokenRefreshBefore - time.Second)
+	if second, _ := cache.Get("id.secret"); second != first {
+		t.Fatal("token should be reused before the refresh window")
+	}
+
+	now = now.Add(2 * time.Second)
+	if third, _ := cache.Get("id.secret"); third == first {
+		t.Fatal("token should be refreshed inside the refresh window")
+	}
+}
@@ -0,0 +1,131 @@
+// Package jsonschema 根据 Go 结构体生成 JSON Schema，并对 JSON 数据进行校验。
+// 生成的 Schema 满足 OpenAI 结构化输出的严格模式要求，可直接用于 response_format 与工具参数定义。
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 以下为 JSON Schema 支持的数据类型
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+// Schema 结构体定义了 JSON Schema 的常用子集
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`                 // 数据类型
+	Description          string             `json:"description,omitempty"`          // 字段描述
+	Properties           map[string]*Schema `json:"properties,omitempty"`           // 对象的属性定义
+	Required             []string           `json:"required,omitempty"`             // 对象的必填属性
+	Items                *Schema            `json:"items,omitempty"`                // 数组元素的定义
+	Enum                 []any              `json:"enum,omitempty"`                 // 可选值列表
+	Format               string             `json:"format,omitempty"`               // 字符串格式，如 date-time
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // 是否允许额外属性，值为 bool 或 *Schema
+}
+
+// For 根据类型参数 T 生成 JSON Schema
+func For[T any]() *Schema {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect 根据反射类型生成 JSON Schema。
+// 结构体字段的名称取自 json 标签，未声明 omitempty 的字段为必填字段；
+// 可以通过 description 标签设置字段描述，通过 enum 标签（以逗号分隔）设置可选值。
+func Reflect(t reflect.Type) *Schema {
+	return reflectType(t, map[reflect.Type]bool{})
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// reflectType 递归生成类型对应的 Schema，visiting 用于避免递归类型导致的死循环
+func reflectType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: TypeString, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString}
+		}
+		return &Schema{Type: TypeArray, Items: reflectType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: reflectType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{Type: TypeObject}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: TypeObject, Properties: map[string]*Schema{}, Required: []string{}, AdditionalProperties: false}
+		reflectFields(t, s, visiting)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// reflectFields 将结构体的字段添加到 Schema 中，匿名嵌入的结构体字段会被展开
+func reflectFields(t reflect.Type, s *Schema, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reflectFields(ft, s, visiting)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := reflectType(field.Type, visiting)
+		prop.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			for _, v := range strings.Split(enum, ",") {
+				prop.Enum = append(prop.Enum, strings.TrimSpace(v))
+			}
+		}
+
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
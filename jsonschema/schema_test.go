@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+)
+
+type address struct {
+	City string `json:"city" description:"城市"`
+}
+
+type person struct {
+	Name    string            `json:"name" description:"姓名"`
+	Age     int               `json:"age"`
+	Gender  string            `json:"gender" enum:"male,female"`
+	Tags    []string          `json:"tags,omitempty"`
+	Address *address          `json:"address"`
+	Extra   map[string]string `json:"extra,omitempty"`
+	Ignored string            `json:"-"`
+}
+
+func Test_For(t *testing.T) {
+	s := For[person]()
+	if s.Type != TypeObject || s.AdditionalProperties != false {
+		t.Fatalf("unexpected root schema: %+v", s)
+	}
+	if got, want := s.Required, []string{"name", "age", "gender", "address"}; len(got) != len(want) {
+		t.Fatalf("required = %v, want %v", got, want)
+	}
+	if _, ok := s.Properties["Ignored"]; ok {
+		t.Fatal("fields tagged with json:\"-\" should be skipped")
+	}
+	if s.Properties["age"].Type != TypeInteger || s.Properties["tags"].Items.Type != TypeString {
+		t.Fatal("unexpected property types")
+	}
+	if s.Properties["address"].Properties["city"].Description != "城市" {
+		t.Fatal("nested description should be kept")
+	}
+	if len(s.Properties["gender"].Enum) != 2 {
+		t.Fatal("enum should be parsed from the tag")
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	s := For[person]()
+
+	var ok any
+	_ = sonic.ConfigDefault.UnmarshalFromString(`{"name":"李白","age":61,"gender":"male","address":{"city":"长安"}}`, &ok)
+	if err := Validate(s, ok); err != nil {
+		t.Fatal(err)
+	}
+
+	var bad any
+	_ = sonic.ConfigDefault.UnmarshalFromString(`{"name":1,"age":1.5,"gender":"unknown","address":{},"other":true}`, &bad)
+	err := Validate(s, bad)
+	verr, isValidation := err.(*ValidationError)
+	if !isValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(verr.Errors) != 5 {
+		t.Fatalf("expected 5 errors, got %d: %v", len(verr.Errors), verr.Errors)
+	}
+}
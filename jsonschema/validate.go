@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidationError 表示 JSON 数据未通过 Schema 校验，Errors 中为每一处不符合的描述
+type ValidationError struct {
+	Errors []string
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return "jsonschema: " + strings.Join(e.Errors, "; ")
+}
+
+// Validate 校验已解码的 JSON 数据（map[string]any、[]any、string、float64、bool、nil）是否符合 Schema。
+// 校验通过时返回 nil，否则返回 *ValidationError。
+func Validate(s *Schema, value any) error {
+	var errs []string
+	validate(s, value, "$", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// validate 递归校验数据，将错误追加到 errs 中
+func validate(s *Schema, value any, path string, errs *[]string) {
+	if s == nil {
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: must be one of %v", path, s.Enum))
+	}
+
+	switch s.Type {
+	case "":
+		return
+	case TypeObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be an object", path))
+			return
+		}
+		validateObject(s, obj, path, errs)
+	case TypeArray:
+		arr, ok := value.([]any)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be an array", path))
+			return
+		}
+		for i, item := range arr {
+			validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case TypeString:
+		if _, ok := value.(string); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be a string", path))
+		}
+	case TypeNumber:
+		if _, ok := value.(float64); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be a number", path))
+		}
+	case TypeInteger:
+		if f, ok := value.(float64); !ok || f != math.Trunc(f) {
+			*errs = append(*errs, fmt.Sprintf("%s: must be an integer", path))
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be a boolean", path))
+		}
+	case TypeNull:
+		if value != nil {
+			*errs = append(*errs, fmt.Sprintf("%s: must be null", path))
+		}
+	}
+}
+
+// validateObject 校验对象的必填属性、属性类型与额外属性
+func validateObject(s *Schema, obj map[string]any, path string, errs *[]string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, fmt.Sprintf("%s.%s: is required", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			validate(prop, obj[name], path+"."+name, errs)
+			continue
+		}
+
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				*errs = append(*errs, fmt.Sprintf("%s.%s: is not allowed", path, name))
+			}
+		case *Schema:
+			validate(extra, obj[name], path+"."+name, errs)
+		}
+	}
+}
+
+// inEnum 判断数据是否为可选值之一
+func inEnum(enum []any, value any) bool {
+	for _, v := range enum {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+// Package mcp 实现了 Model Context Protocol（MCP）的客户端。
+// 它可以通过 stdio 或 Streamable HTTP 连接 MCP 服务，列出服务提供的工具与资源，
+// 并将工具转换为 request.Request 中的函数定义，使任意服务商的模型都可以调用 MCP 工具。
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/agent"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+// ProtocolVersion 是客户端使用的 MCP 协议版本
+const ProtocolVersion = "2025-03-26"
+
+// supportedVersions 是客户端支持的协议版本，服务端协商的版本不在其中时连接失败
+var supportedVersions = []string{ProtocolVersion, "2024-11-05"}
+
+// Implementation 描述 MCP 客户端或服务的名称与版本
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool 是 MCP 服务提供的工具
+type Tool struct {
+	Name        string         `json:"name"`                  // 工具名称
+	Description string         `json:"description,omitempty"` // 工具描述
+	InputSchema map[string]any `json:"inputSchema"`           // 参数的 JSON Schema
+}
+
+// Content 是工具调用结果或提示词中的一段内容
+type Content struct {
+	Type     string `json:"type"`               // 内容类型：text、image、audio、resource
+	Text     string `json:"text,omitempty"`     // 文本内容
+	Data     string `json:"data,omitempty"`     // base64 编码的二进制内容
+	MimeType string `json:"mimeType,omitempty"` // 二进制内容的类型
+}
+
+// CallToolResult 是工具调用的结果
+type CallToolResult struct {
+	Content []Content `json:"content"`           // 结果内容
+	IsError bool      `json:"isError,omitempty"` // 工具执行是否出错，出错时 Content 中为错误描述
+}
+
+// Text 返回结果中所有文本内容的拼接，非文本内容以占位符表示
+func (r *CallToolResult) Text() string {
+	parts := make([]string, 0, len(r.Content))
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+		} else {
+			parts = append(parts, "["+c.Type+" "+c.MimeType+"]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Resource 是 MCP 服务提供的资源
+type Resource struct {
+	URI         string `json:"uri"`                   // 资源地址
+	Name        string `json:"name"`                  // 资源名称
+	Description string `json:"description,omitempty"` // 资源描述
+	MimeType    string `json:"mimeType,omitempty"`    // 资源类型
+}
+
+// ResourceContents 是资源的内容
+type ResourceContents struct {
+	URI      string `json:"uri"`                // 资源地址
+	MimeType string `json:"mimeType,omitempty"` // 资源类型
+	Text     string `json:"text,omitempty"`     // 文本资源的内容
+	Blob     string `json:"blob,omitempty"`     // base64 编码的二进制资源内容
+}
+
+// Client 是 MCP 客户端，可以被多个协程同时使用
+type Client struct {
+	t      Transport
+	nextID atomic.Int64
+
+	ServerInfo   Implementation // 服务的名称与版本
+	Instructions string         // 服务提供的使用说明，可以放入系统提示词
+}
+
+// Connect 通过指定的传输方式连接 MCP 服务并完成初始化握手，握手失败时会关闭 t。
+// 服务端协商的协议版本不受支持时返回 errorx.NotSupported。
+func Connect(ctx context.Context, t Transport) (_ *Client, err error) {
+	defer func() {
+		if err != nil {
+			_ = t.Close()
+		}
+	}()
+	c := &Client{t: t}
+
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions"`
+	}
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      Implementation{Name: "uniai", Version: "1.0.0"},
+	}
+	if err := c.call(ctx, "initialize", params, &result); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(supportedVersions, result.ProtocolVersion) {
+		return nil, errors.Wrapf(errorx.NotSupported, "mcp: unsupported protocol version %q", result.ProtocolVersion)
+	}
+	c.ServerInfo = result.ServerInfo
+	c.Instructions = result.Instructions
+
+	if err := t.notify(ctx, &message{JSONRPC: jsonrpcVersion, Method: "notifications/initialized"}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListTools 列出服务提供的所有工具，分页结果会被自动合并
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", cursorParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if cursor = page.NextCursor; cursor == "" {
+			return tools, nil
+		}
+	}
+}
+
+// CallTool 调用工具，arguments 为 JSON 字符串、json.RawMessage 或可以序列化为 JSON 对象的值
+func (c *Client) CallTool(ctx context.Context, name string, arguments any) (*CallToolResult, error) {
+	switch v := arguments.(type) {
+	case string:
+		if v == "" {
+			v = "{}"
+		}
+		arguments = json.RawMessage(v)
+	case nil:
+		arguments = map[string]any{}
+	}
+
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources 列出服务提供的所有资源，分页结果会被自动合并
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	cursor := ""
+	for {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "resources/list", cursorParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Resources...)
+		if cursor = page.NextCursor; cursor == "" {
+			return resources, nil
+		}
+	}
+}
+
+// ReadResource 读取资源的内容
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	if err := c.call(ctx, "resources/read", map[string]any{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// RequestTools 将服务提供的工具转换为请求中的函数定义
+func (c *Client) RequestTools(ctx context.Context) ([]request.Tool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]request.Tool, 0, len(tools))
+	for _, t := range tools {
+		out = append(out, request.NewFunctionTool(t.Name, t.Description, t.InputSchema))
+	}
+	return out, nil
+}
+
+// Execute 将模型发起的工具调用转发给 MCP 服务，并返回可以直接追加到消息列表的工具消息。
+// 工具执行出错时错误描述同样作为工具消息返回，只有通信失败时才返回错误。
+func (c *Client) Execute(ctx context.Context, call request.ToolCall) (request.Messages, error) {
+	result, err := c.CallTool(ctx, call.Function.Name, call.Function.Arguments)
+	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			return request.NewToolMessage(call.ID, "error: "+rpcErr.Message), nil
+		}
+		return request.Messages{}, err
+	}
+
+	text := result.Text()
+	if result.IsError {
+		text = "error: " + text
+	}
+	return request.NewToolMessage(call.ID, text), nil
+}
+
+// AgentTools 将服务提供的工具转换为 agent.Tool，可以直接注册到 agent.Runner 中
+func (c *Client) AgentTools(ctx context.Context) ([]agent.Tool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]agent.Tool, 0, len(tools))
+	for _, t := range tools {
+		name := t.Name
+		out = append(out, agent.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  t.InputSchema,
+			Handler: func(ctx context.Context, arguments string) (string, error) {
+				result, err := c.CallTool(ctx, name, arguments)
+				if err != nil {
+					return "", err
+				}
+				if result.IsError {
+					return "", errors.New(result.Text())
+				}
+				return result.Text(), nil
+			},
+		})
+	}
+	return out, nil
+}
+
+// Close 关闭与服务的连接
+func (c *Client) Close() error {
+	return c.t.Close()
+}
+
+// call 发送请求并将结果解码到 out 中
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	id := c.nextID.Add(1)
+	resp, err := c.t.roundTrip(ctx, &message{JSONRPC: jsonrpcVersion, ID: newID(id), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return sonic.ConfigDefault.Unmarshal(resp.Result, out)
+}
+
+// cursorParams 返回分页请求的参数
+func cursorParams(cursor string) map[string]any {
+	if cursor == "" {
+		return map[string]any{}
+	}
+	return map[string]any{"cursor": cursor}
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// jsonrpcVersion 是 MCP 使用的 JSON-RPC 版本
+const jsonrpcVersion = "2.0"
+
+// message 是 JSON-RPC 2.0 的消息，请求、通知与响应共用该结构。
+// 标识保留原始的 JSON，服务端发起的请求可能使用字符串作为标识，回复时需要原样返回。
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// isResponse 判断消息是否为响应
+func (m *message) isResponse() bool {
+	return len(m.ID) > 0 && m.Method == ""
+}
+
+// newID 返回数字形式的请求标识
+func newID(id int64) json.RawMessage {
+	return json.RawMessage(strconv.FormatInt(id, 10))
+}
+
+// RPCError 是 MCP 服务返回的 JSON-RPC 错误
+type RPCError struct {
+	Code    int    `json:"code"`           // 错误码，如 -32601 表示方法不存在
+	Message string `json:"message"`        // 错误描述
+	Data    any    `json:"data,omitempty"` // 附加信息
+}
+
+// Error 实现 error 接口
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp: rpc error %d: %s", e.Code, e.Message)
+}
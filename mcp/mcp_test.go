@@ -0,0 +1,239 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+
+	"github.com/jun3372/uniai/request"
+)
+
+// 以环境变量启动测试二进制时，作为 stdio MCP 服务运行，hang 模式下标准输入关闭后不退出
+func TestMain(m *testing.M) {
+	switch os.Getenv("UNIAI_MCP_TEST_SERVER") {
+	case "1":
+		serve(os.Stdin, os.Stdout)
+		return
+	case "hang":
+		serve(os.Stdin, os.Stdout)
+		time.Sleep(time.Hour)
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// serve 是一个最小的 stdio MCP 服务
+func serve(r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var msg message
+		if err := sonic.ConfigDefault.Unmarshal(scanner.Bytes(), &msg); err != nil || msg.ID == nil {
+			continue
+		}
+		data, _ := sonic.ConfigDefault.Marshal(dispatch(&msg))
+		fmt.Fprintf(w, "%s\n", data)
+	}
+}
+
+// dispatch 处理一个请求并返回响应
+func dispatch(req *message) *message {
+	resp := &message{JSONRPC: jsonrpcVersion, ID: req.ID}
+	params, _ := req.Params.(map[string]any)
+
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = map[string]any{"protocolVersion": ProtocolVersion, "serverInfo": Implementation{Name: "test", Version: "1"}, "capabilities": map[string]any{}}
+	case "tools/list":
+		// 分两页返回工具列表
+		if params["cursor"] == nil {
+			result = map[string]any{"tools": []Tool{{Name: "add", Description: "两数相加", InputSchema: map[string]any{"type": "object"}}}, "nextCursor": "2"}
+		} else {
+			result = map[string]any{"tools": []Tool{{Name: "fail", InputSchema: map[string]any{"type": "object"}}}}
+		}
+	case "tools/call":
+		args, _ := params["arguments"].(map[string]any)
+		switch params["name"] {
+		case "add":
+			a, _ := args["a"].(float64)
+			b, _ := args["b"].(float64)
+			result = CallToolResult{Content: []Content{{Type: "text", Text: fmt.Sprint(a + b)}}}
+		case "fail":
+			result = CallToolResult{Content: []Content{{Type: "text", Text: "boom"}}, IsError: true}
+		default:
+			resp.Error = &RPCError{Code: -32602, Message: "unknown tool"}
+		}
+	case "resources/list":
+		result = map[string]any{"resources": []Resource{{URI: "file:///readme", Name: "readme"}}}
+	case "resources/read":
+		result = map[string]any{"contents": []ResourceContents{{URI: params["uri"].(string), Text: "hello"}}}
+	default:
+		resp.Error = &RPCError{Code: -32601, Message: "method not found"}
+	}
+
+	if result != nil {
+		resp.Result, _ = sonic.ConfigDefault.Marshal(result)
+	}
+	return resp
+}
+
+// exercise 通过客户端调用测试服务的所有能力
+func exercise(t *testing.T, tr Transport) {
+	ctx := context.Background()
+	c, err := Connect(ctx, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if c.ServerInfo.Name != "test" {
+		t.Fatalf("ServerInfo = %+v", c.ServerInfo)
+	}
+
+	tools, err := c.RequestTools(ctx)
+	if err != nil || len(tools) != 2 || tools[0].Function.Name != "add" {
+		t.Fatalf("RequestTools = %+v, %v", tools, err)
+	}
+
+	tests := map[string]string{
+		"add":     "3",
+		"fail":    "error: boom",
+		"missing": "error: unknown tool",
+	}
+	for name, want := range tests {
+		msg, err := c.Execute(ctx, request.ToolCall{ID: name, Function: request.FunctionCall{Name: name, Arguments: `{"a":1,"b":2}`}})
+		if err != nil || msg.Role != request.MessageRoleTool || msg.ToolCallID != name || msg.Content != want {
+			t.Errorf("Execute(%s) = %+v, %v, want %q", name, msg, err, want)
+		}
+	}
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || len(resources) != 1 {
+		t.Fatalf("ListResources = %+v, %v", resources, err)
+	}
+	contents, err := c.ReadResource(ctx, resources[0].URI)
+	if err != nil || contents[0].Text != "hello" {
+		t.Fatalf("ReadResource = %+v, %v", contents, err)
+	}
+}
+
+func Test_Stdio(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("UNIAI_MCP_TEST_SERVER", "1")
+
+	tr, err := NewCommand(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exercise(t, tr)
+
+	// 标准输入关闭后不退出的服务在超时后被强制结束
+	defer func(d time.Duration) { closeTimeout = d }(closeTimeout)
+	closeTimeout = 100 * time.Millisecond
+	t.Setenv("UNIAI_MCP_TEST_SERVER", "hang")
+	tr, err = NewCommand(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := tr.Close(); err == nil || time.Since(start) > 5*time.Second {
+		t.Fatalf("Close = %v after %s, want killed", err, time.Since(start))
+	}
+}
+
+func Test_HTTP(t *testing.T) {
+	var sessions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			return
+		}
+		sessions = append(sessions, r.Header.Get("Mcp-Session-Id"))
+
+		var msg message
+		if err := sonic.ConfigDefault.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if msg.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Mcp-Session-Id", "s1")
+		data, _ := sonic.ConfigDefault.Marshal(dispatch(&msg))
+		// 工具调用以 SSE 返回，并在响应前插入一条通知。
+		if msg.Method == "tools/call" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notifications/progress"}`, data)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	exercise(t, NewHTTP(srv.URL, nil, WithHTTPClient(srv.Client())))
+	if sessions[0] != "" || sessions[1] != "s1" || strings.Join(sessions[1:], "") != strings.Repeat("s1", len(sessions)-1) {
+		t.Fatalf("unexpected sessions: %v", sessions)
+	}
+}
+
+func Test_ProtocolVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"1999-01-01","serverInfo":{"name":"old","version":"1"}}}`)
+	}))
+	defer srv.Close()
+
+	tr := &closeCounter{Transport: NewHTTP(srv.URL, nil)}
+	if _, err := Connect(context.Background(), tr); !errors.Is(err, errorx.NotSupported) {
+		t.Fatalf("err = %v, want NotSupported", err)
+	}
+	if tr.closed != 1 {
+		t.Fatalf("failed handshake should close the transport, closed %d times", tr.closed)
+	}
+}
+
+// closeCounter 记录传输通道被关闭的次数
+type closeCounter struct {
+	Transport
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return c.Transport.Close()
+}
+
+func Test_ServerRequest(t *testing.T) {
+	in, server := io.Pipe()
+	replies, out := io.Pipe()
+	tr := NewStdio(in, out)
+	defer tr.Close()
+
+	// 服务端发起的请求使用字符串标识，回复时需要原样返回
+	go fmt.Fprintln(server, `{"jsonrpc":"2.0","id":"srv-1","method":"ping"}`)
+	line, err := bufio.NewReader(replies).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reply message
+	if err := sonic.ConfigDefault.UnmarshalFromString(line, &reply); err != nil || string(reply.ID) != `"srv-1"` || string(reply.Result) != "{}" {
+		t.Fatalf("reply = %s, %v", line, err)
+	}
+}
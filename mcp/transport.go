@@ -0,0 +1,346 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alevinval/sse/pkg/decoder"
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+)
+
+// ErrClosed 表示传输通道已关闭
+var ErrClosed = errors.New("mcp: transport closed")
+
+// closeTimeout 是关闭子进程的标准输入后等待其退出的最长时间，超时后子进程会被强制结束
+var closeTimeout = 5 * time.Second
+
+// Transport 接口定义了与 MCP 服务通信的传输方式，内置 stdio 与 Streamable HTTP 两种实现。
+type Transport interface {
+	// roundTrip 发送请求并等待对应的响应
+	roundTrip(ctx context.Context, req *message) (*message, error)
+	// notify 发送不需要响应的通知
+	notify(ctx context.Context, msg *message) error
+	// Close 关闭传输通道并释放资源
+	Close() error
+}
+
+// stdio 是基于标准输入输出的传输方式，每条消息为一行 JSON
+type stdio struct {
+	w      io.Writer
+	closer func() error
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]chan *message
+	err     error // 读取结束的原因，非 nil 表示通道已关闭
+}
+
+// NewStdio 创建一个基于读写流的传输方式，r 为服务端的输出，w 为服务端的输入。
+// 适用于在进程内通过 io.Pipe 连接 MCP 服务，或接管已经启动的子进程。
+func NewStdio(r io.Reader, w io.WriteCloser) Transport {
+	t := &stdio{w: w, closer: w.Close, pending: make(map[string]chan *message)}
+	go t.read(r)
+	return t
+}
+
+// NewCommand 启动一个子进程作为 MCP 服务，并通过其标准输入输出通信。
+// 子进程的标准错误输出会被丢弃，关闭传输通道时会关闭子进程的标准输入并等待其退出，
+// 子进程在 5 秒内没有退出时会被强制结束。
+func NewCommand(name string, args ...string) (Transport, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	t := &stdio{w: stdin, pending: make(map[string]chan *message)}
+	t.closer = func() error {
+		stdin.Close()
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(closeTimeout):
+			cmd.Process.Kill()
+			return errors.Wrapf(<-done, "mcp: server did not exit within %s and was killed", closeTimeout)
+		}
+	}
+	go t.read(stdout)
+	return t, nil
+}
+
+// read 持续读取服务端的消息，将响应分发给等待中的请求
+func (t *stdio) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg message
+		if err := sonic.ConfigDefault.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		if msg.isResponse() {
+			t.deliver(&msg)
+			continue
+		}
+		// 服务端发起的请求目前仅支持 ping，其余请求回复方法不存在。
+		if len(msg.ID) > 0 {
+			reply := &message{JSONRPC: jsonrpcVersion, ID: msg.ID, Result: []byte("{}")}
+			if msg.Method != "ping" {
+				reply = &message{JSONRPC: jsonrpcVersion, ID: msg.ID, Error: &RPCError{Code: -32601, Message: "method not found"}}
+			}
+			t.write(reply)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = ErrClosed
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+	for id, ch := range t.pending {
+		close(ch)
+		delete(t.pending, id)
+	}
+}
+
+// deliver 将响应交给对应的请求
+func (t *stdio) deliver(msg *message) {
+	t.mu.Lock()
+	ch, ok := t.pending[string(msg.ID)]
+	delete(t.pending, string(msg.ID))
+	t.mu.Unlock()
+
+	if ok {
+		ch <- msg
+	}
+}
+
+// write 写入一行消息
+func (t *stdio) write(msg *message) error {
+	data, err := sonic.ConfigDefault.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.w.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdio) roundTrip(ctx context.Context, req *message) (*message, error) {
+	ch := make(chan *message, 1)
+	t.mu.Lock()
+	if t.err != nil {
+		t.mu.Unlock()
+		return nil, t.err
+	}
+	t.pending[string(req.ID)] = ch
+	t.mu.Unlock()
+
+	if err := t.write(req); err != nil {
+		t.mu.Lock()
+		delete(t.pending, string(req.ID))
+		t.mu.Unlock()
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, ErrClosed
+		}
+		return resp, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		delete(t.pending, string(req.ID))
+		t.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdio) notify(_ context.Context, msg *message) error {
+	return t.write(msg)
+}
+
+// Close 关闭服务端的输入，服务端应随之退出
+func (t *stdio) Close() error {
+	return t.closer()
+}
+
+// streamableHTTP 是基于 Streamable HTTP 的传输方式。
+// 每个请求以 POST 发送，服务端可以返回 JSON 或 SSE 流，会话标识通过 Mcp-Session-Id 请求头传递。
+type streamableHTTP struct {
+	url    string
+	header http.Header
+	client *http.Client
+
+	mu      sync.Mutex
+	session string
+}
+
+// HTTPOptions 结构体定义了 Streamable HTTP 传输方式的选项
+type HTTPOptions struct {
+	Client *http.Client // 发送请求使用的 HTTP 客户端，为 nil 时使用 http.DefaultClient
+}
+
+// HTTPOption 是一个函数类型，用于修改 HTTPOptions 结构体
+type HTTPOption func(*HTTPOptions)
+
+// WithHTTPClient 设置发送请求使用的 HTTP 客户端，可用于设置超时、代理与 TLS 配置
+func WithHTTPClient(c *http.Client) HTTPOption {
+	return func(o *HTTPOptions) { o.Client = c }
+}
+
+// NewHTTP 创建一个 Streamable HTTP 传输方式，header 会附加到每个请求中，如 Authorization。
+func NewHTTP(url string, header http.Header, opts ...HTTPOption) Transport {
+	o := HTTPOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+	return &streamableHTTP{url: url, header: header, client: o.Client}
+}
+
+func (t *streamableHTTP) roundTrip(ctx context.Context, req *message) (*message, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var msg message
+		if err := sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, errors.Wrap(errorx.InvalidOutput, err.Error())
+		}
+		return &msg, nil
+	}
+
+	// SSE 流中可能包含服务端的通知，读取到与请求标识相同的响应为止。
+	code := decoder.New(resp.Body)
+	for {
+		event, err := code.Decode()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.Wrap(errorx.InvalidOutput, "mcp: stream ended without response")
+			}
+			return nil, err
+		}
+
+		var msg message
+		if err := sonic.ConfigDefault.UnmarshalFromString(event.Data, &msg); err != nil {
+			continue
+		}
+		if msg.isResponse() && bytes.Equal(msg.ID, req.ID) {
+			return &msg, nil
+		}
+	}
+}
+
+func (t *streamableHTTP) notify(ctx context.Context, msg *message) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// post 发送一条消息，响应状态码不是 2xx 时返回 *errorx.APIError
+func (t *streamableHTTP) post(ctx context.Context, msg *message) (*http.Response, error) {
+	data, err := sonic.ConfigDefault.Marshal(msg)
+	if err != nil {
+		return nil, errorx.InvalidInput
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, errorx.InvalidInput
+	}
+	for k, v := range t.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	t.mu.Lock()
+	if t.session != "" {
+		req.Header.Set("Mcp-Session-Id", t.session)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.Wrap(errorx.InvalidRequest, err.Error())
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errorx.NewAPIError("mcp", resp.StatusCode, body)
+	}
+
+	if session := resp.Header.Get("Mcp-Session-Id"); session != "" {
+		t.mu.Lock()
+		t.session = session
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// Close 结束服务端的会话
+func (t *streamableHTTP) Close() error {
+	t.mu.Lock()
+	session := t.session
+	t.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range t.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Mcp-Session-Id", session)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,48 @@
+package uniai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jun3372/uniai/catalog"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+)
+
+func Test_ListModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"object":"list","data":[{"id":"gpt-4o","object":"model","created":1,"owned_by":"system"}]}`))
+	}))
+	defer srv.Close()
+
+	resp, err := New(client.WithHost(srv.URL)).ListModels(context.Background())
+	if err != nil || len(resp.Data) != 1 || resp.Data[0].ID != "gpt-4o" {
+		t.Fatalf("ListModels = %+v, %v", resp, err)
+	}
+
+	// 混元没有模型列表接口，返回内置目录中的模型。
+	resp, err = New(client.WithType(client.Hunyuan)).ListModels(context.Background())
+	if err != nil || len(resp.Data) == 0 {
+		t.Fatalf("ListModels = %+v, %v", resp, err)
+	}
+}
+
+func Test_Validator(t *testing.T) {
+	ai := New(client.WithHost("http://127.0.0.1:0"), client.WithValidator(catalog.Default))
+	_, err := ai.Completions(context.Background(), *request.NewRequest(
+		request.WithModel("gpt-3.5-turbo"),
+		request.WithMaxTokens(100000),
+		request.WithMessages([]request.Messages{request.NewUserMessage("hi")}),
+	))
+	if !errors.Is(err, errorx.InvalidInput) {
+		t.Fatalf("err = %v, want InvalidInput", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package poll 提供异步任务的通用轮询工具。
+// 通义万相等服务商的异步接口先返回任务 ID，再由调用方轮询任务状态，直到任务完成或失败。
+package poll
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMaxAttempts 表示轮询次数已用尽而任务仍未完成
+var ErrMaxAttempts = errors.New("poll: max attempts exceeded")
+
+// Options 结构体定义了轮询的选项
+type Options struct {
+	Interval    time.Duration // 首次轮询前的等待时间
+	MaxInterval time.Duration // 两次轮询之间的最长等待时间
+	Multiplier  float64       // 每次轮询后等待时间的增长倍数
+	MaxAttempts int           // 最大轮询次数，0 表示不限制，由 ctx 控制超时
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// NewOptions 创建一个新的 Options 实例，默认首次等待 1 秒，每次增长 1.5 倍，最长等待 10 秒。
+func NewOptions(opts ...Option) *Options {
+	o := &Options{Interval: time.Second, MaxInterval: 10 * time.Second, Multiplier: 1.5}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithInterval 设置首次轮询前的等待时间
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) { o.Interval = d }
+}
+
+// WithMaxInterval 设置两次轮询之间的最长等待时间
+func WithMaxInterval(d time.Duration) Option {
+	return func(o *Options) { o.MaxInterval = d }
+}
+
+// WithMultiplier 设置每次轮询后等待时间的增长倍数，小于 1 时按 1 处理
+func WithMultiplier(m float64) Option {
+	return func(o *Options) { o.Multiplier = m }
+}
+
+// WithMaxAttempts 设置最大轮询次数
+func WithMaxAttempts(n int) Option {
+	return func(o *Options) { o.MaxAttempts = n }
+}
+
+// Until 按指数退避反复调用 check，直到 check 返回 done 为 true 或返回错误。
+// ctx 被取消或超时时立即返回 ctx.Err()，轮询次数用尽时返回 ErrMaxAttempts。
+func Until[T any](ctx context.Context, check func(ctx context.Context) (result T, done bool, err error), opts ...Option) (T, error) {
+	o := NewOptions(opts...)
+	if o.Multiplier < 1 {
+		o.Multiplier = 1
+	}
+
+	var zero T
+	interval := o.Interval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for attempt := 1; o.MaxAttempts <= 0 || attempt <= o.MaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+
+		result, done, err := check(ctx)
+		if err != nil {
+			return zero, err
+		}
+		if done {
+			return result, nil
+		}
+
+		interval = time.Duration(float64(interval) * o.Multiplier)
+		if o.MaxInterval > 0 && interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+		timer.Reset(interval)
+	}
+
+	return zero, ErrMaxAttempts
+}
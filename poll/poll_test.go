@@ -0,0 +1,36 @@
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func Test_Until(t *testing.T) {
+	calls := 0
+	got, err := Until(context.Background(), func(ctx context.Context) (string, bool, error) {
+		calls++
+		return "done", calls == 3, nil
+	}, WithInterval(time.Millisecond), WithMultiplier(2))
+	if err != nil || got != "done" || calls != 3 {
+		t.Fatalf("got %q, calls %d, err %v", got, calls, err)
+	}
+
+	_, err = Until(context.Background(), func(ctx context.Context) (int, bool, error) {
+		return 0, false, nil
+	}, WithInterval(time.Millisecond), WithMaxAttempts(2))
+	if !errors.Is(err, ErrMaxAttempts) {
+		t.Fatalf("expected ErrMaxAttempts, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = Until(ctx, func(ctx context.Context) (int, bool, error) {
+		return 0, false, nil
+	}, WithInterval(5*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
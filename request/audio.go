@@ -0,0 +1,108 @@
+package request
+
+import "io"
+
+// TranscriptionRequest 结构体定义了语音转文字请求的参数
+type TranscriptionRequest struct {
+	Model                  string    // Model 字段表示使用的语音识别模型，如 whisper-1
+	File                   io.Reader // File 字段表示需要识别的音频内容
+	FileName               string    // FileName 字段表示音频的文件名，服务商会根据扩展名判断音频格式
+	Language               string    // Language 字段表示音频的语言，使用 ISO-639-1 格式，如 zh、en
+	Prompt                 string    // Prompt 字段表示用于引导识别风格或延续上一段音频的文本
+	ResponseFormat         string    // ResponseFormat 字段表示识别结果的格式，可选 json、text、srt、verbose_json、vtt
+	Temperature            *float32  // Temperature 字段表示采样温度，为 nil 时使用默认值
+	TimestampGranularities []string  // TimestampGranularities 字段表示时间戳的粒度，可选 word、segment，仅 verbose_json 格式有效
+	Endpoint               string    // Endpoint 字段表示请求的端点
+}
+
+// TranscriptionOption 是一个函数类型，用于修改 TranscriptionRequest 结构体
+type TranscriptionOption func(*TranscriptionRequest)
+
+// NewTranscriptionRequest 创建并返回一个新的 TranscriptionRequest 实例。
+func NewTranscriptionRequest(opts ...TranscriptionOption) *TranscriptionRequest {
+	resp := &TranscriptionRequest{}
+	for _, fn := range opts {
+		fn(resp)
+	}
+	return resp
+}
+
+// WithTranscriptionModel 设置语音识别使用的模型
+func WithTranscriptionModel(model string) TranscriptionOption {
+	return func(r *TranscriptionRequest) { r.Model = model }
+}
+
+// WithFile 设置需要识别的音频内容与文件名
+func WithFile(name string, file io.Reader) TranscriptionOption {
+	return func(r *TranscriptionRequest) {
+		r.FileName = name
+		r.File = file
+	}
+}
+
+// WithLanguage 设置音频的语言
+func WithLanguage(language string) TranscriptionOption {
+	return func(r *TranscriptionRequest) { r.Language = language }
+}
+
+// WithTranscriptionPrompt 设置用于引导识别的文本
+func WithTranscriptionPrompt(prompt string) TranscriptionOption {
+	return func(r *TranscriptionRequest) { r.Prompt = prompt }
+}
+
+// WithTranscriptionResponseFormat 设置识别结果的格式
+func WithTranscriptionResponseFormat(format string) TranscriptionOption {
+	return func(r *TranscriptionRequest) { r.ResponseFormat = format }
+}
+
+// WithTimestampGranularities 设置时间戳的粒度
+func WithTimestampGranularities(granularities ...string) TranscriptionOption {
+	return func(r *TranscriptionRequest) { r.TimestampGranularities = granularities }
+}
+
+// SpeechRequest 结构体定义了文字转语音请求的参数
+type SpeechRequest struct {
+	Model          string   `json:"model"`                     // Model 字段表示使用的语音合成模型，如 tts-1
+	Input          string   `json:"input"`                     // Input 字段表示需要合成的文本
+	Voice          string   `json:"voice"`                     // Voice 字段表示音色，如 alloy、nova
+	ResponseFormat string   `json:"response_format,omitempty"` // ResponseFormat 字段表示音频格式，可选 mp3、opus、aac、flac、wav、pcm
+	Speed          *float32 `json:"speed,omitempty"`           // Speed 字段表示语速，取值范围为 0.25 到 4.0，为 nil 时使用默认值
+	Endpoint       string   `json:"-"`                         // Endpoint 字段表示请求的端点
+}
+
+// SpeechOption 是一个函数类型，用于修改 SpeechRequest 结构体
+type SpeechOption func(*SpeechRequest)
+
+// NewSpeechRequest 创建并返回一个新的 SpeechRequest 实例。
+func NewSpeechRequest(opts ...SpeechOption) *SpeechRequest {
+	resp := &SpeechRequest{}
+	for _, fn := range opts {
+		fn(resp)
+	}
+	return resp
+}
+
+// WithSpeechModel 设置语音合成使用的模型
+func WithSpeechModel(model string) SpeechOption {
+	return func(r *SpeechRequest) { r.Model = model }
+}
+
+// WithSpeechInput 设置需要合成的文本
+func WithSpeechInput(input string) SpeechOption {
+	return func(r *SpeechRequest) { r.Input = input }
+}
+
+// WithVoice 设置音色
+func WithVoice(voice string) SpeechOption {
+	return func(r *SpeechRequest) { r.Voice = voice }
+}
+
+// WithSpeechResponseFormat 设置音频格式
+func WithSpeechResponseFormat(format string) SpeechOption {
+	return func(r *SpeechRequest) { r.ResponseFormat = format }
+}
+
+// WithSpeed 设置语速
+func WithSpeed(speed float32) SpeechOption {
+	return func(r *SpeechRequest) { r.Speed = &speed }
+}
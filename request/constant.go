@@ -4,4 +4,40 @@ const (
 	MessageRoleSystem    = "system"
 	MessageRoleUser      = "user"
 	MessageRoleAssistant = "assistant"
+	MessageRoleTool      = "tool"
+)
+
+const (
+	ToolTypeFunction  = "function"
+	ToolTypeWebSearch = "web_search"
+)
+
+const (
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+const (
+	EncodingFormatFloat  = "float"
+	EncodingFormatBase64 = "base64"
+)
+
+const (
+	ImageResponseFormatURL     = "url"
+	ImageResponseFormatB64JSON = "b64_json"
+)
+
+const (
+	TranscriptionFormatJSON        = "json"
+	TranscriptionFormatText        = "text"
+	TranscriptionFormatSRT         = "srt"
+	TranscriptionFormatVerboseJSON = "verbose_json"
+	TranscriptionFormatVTT         = "vtt"
 )
@@ -0,0 +1,49 @@
+package request
+
+// EmbeddingRequest 结构体定义了向量化请求的参数
+type EmbeddingRequest struct {
+	Model          string   `json:"model"`                     // Model 字段表示使用的向量模型
+	Input          []string `json:"input"`                     // Input 字段表示需要向量化的文本列表
+	Dimensions     *int     `json:"dimensions,omitempty"`      // Dimensions 字段表示输出向量的维度，为 nil 时使用模型的默认维度
+	EncodingFormat string   `json:"encoding_format,omitempty"` // EncodingFormat 字段表示向量的传输格式，可选 float、base64
+	User           string   `json:"user,omitempty"`            // User 字段表示终端用户的唯一标识
+	BatchSize      int      `json:"-"`                         // BatchSize 字段表示单次请求的最大文本条数，为 0 时使用服务商的默认上限
+	Endpoint       string   `json:"-"`                         // Endpoint 字段表示请求的端点
+}
+
+// EmbeddingOption 是一个函数类型，用于修改 EmbeddingRequest 结构体
+type EmbeddingOption func(*EmbeddingRequest)
+
+// NewEmbeddingRequest 创建并返回一个新的 EmbeddingRequest 实例。
+func NewEmbeddingRequest(opts ...EmbeddingOption) *EmbeddingRequest {
+	resp := &EmbeddingRequest{Input: []string{}}
+	for _, fn := range opts {
+		fn(resp)
+	}
+	return resp
+}
+
+// WithEmbeddingModel 设置向量化请求使用的模型
+func WithEmbeddingModel(model string) EmbeddingOption {
+	return func(r *EmbeddingRequest) { r.Model = model }
+}
+
+// WithInput 设置需要向量化的文本列表
+func WithInput(input ...string) EmbeddingOption {
+	return func(r *EmbeddingRequest) { r.Input = input }
+}
+
+// WithDimensions 设置输出向量的维度
+func WithDimensions(dimensions int) EmbeddingOption {
+	return func(r *EmbeddingRequest) { r.Dimensions = &dimensions }
+}
+
+// WithEncodingFormat 设置向量的传输格式，base64 格式可以减少传输的数据量，返回结果会被自动解码
+func WithEncodingFormat(format string) EmbeddingOption {
+	return func(r *EmbeddingRequest) { r.EncodingFormat = format }
+}
+
+// WithBatchSize 设置单次请求的最大文本条数，超出时会自动分批请求
+func WithBatchSize(size int) EmbeddingOption {
+	return func(r *EmbeddingRequest) { r.BatchSize = size }
+}
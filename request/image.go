@@ -0,0 +1,63 @@
+package request
+
+// ImageRequest 结构体定义了图像生成请求的参数
+type ImageRequest struct {
+	Model          string `json:"model"`                     // Model 字段表示使用的图像模型，如 dall-e-3、wanx-v1、cogview-3
+	Prompt         string `json:"prompt"`                    // Prompt 字段表示图像的描述
+	NegativePrompt string `json:"negative_prompt,omitempty"` // NegativePrompt 字段表示不希望出现在图像中的内容，仅部分服务商支持
+	Size           string `json:"size,omitempty"`            // Size 字段表示图像尺寸，统一使用 1024x1024 的格式
+	N              int    `json:"n,omitempty"`               // N 字段表示生成的图像数量
+	Style          string `json:"style,omitempty"`           // Style 字段表示图像风格，取值由服务商决定，如 vivid、<watercolor>
+	Quality        string `json:"quality,omitempty"`         // Quality 字段表示图像质量，如 standard、hd
+	ResponseFormat string `json:"response_format,omitempty"` // ResponseFormat 字段表示图像的返回格式，可选 url、b64_json
+	Seed           *int   `json:"seed,omitempty"`            // Seed 字段表示随机种子，仅部分服务商支持
+	User           string `json:"user,omitempty"`            // User 字段表示终端用户的唯一标识
+	Endpoint       string `json:"-"`                         // Endpoint 字段表示请求的端点
+}
+
+// ImageOption 是一个函数类型，用于修改 ImageRequest 结构体
+type ImageOption func(*ImageRequest)
+
+// NewImageRequest 创建并返回一个新的 ImageRequest 实例。
+func NewImageRequest(opts ...ImageOption) *ImageRequest {
+	resp := &ImageRequest{N: 1}
+	for _, fn := range opts {
+		fn(resp)
+	}
+	return resp
+}
+
+// WithImageModel 设置图像生成请求使用的模型
+func WithImageModel(model string) ImageOption {
+	return func(r *ImageRequest) { r.Model = model }
+}
+
+// WithPrompt 设置图像的描述
+func WithPrompt(prompt string) ImageOption {
+	return func(r *ImageRequest) { r.Prompt = prompt }
+}
+
+// WithNegativePrompt 设置不希望出现在图像中的内容
+func WithNegativePrompt(prompt string) ImageOption {
+	return func(r *ImageRequest) { r.NegativePrompt = prompt }
+}
+
+// WithSize 设置图像尺寸，格式为 宽x高，如 1024x1024
+func WithSize(size string) ImageOption {
+	return func(r *ImageRequest) { r.Size = size }
+}
+
+// WithN 设置生成的图像数量
+func WithN(n int) ImageOption {
+	return func(r *ImageRequest) { r.N = n }
+}
+
+// WithStyle 设置图像风格
+func WithStyle(style string) ImageOption {
+	return func(r *ImageRequest) { r.Style = style }
+}
+
+// WithImageResponseFormat 设置图像的返回格式，可选 url、b64_json
+func WithImageResponseFormat(format string) ImageOption {
+	return func(r *ImageRequest) { r.ResponseFormat = format }
+}
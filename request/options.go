@@ -5,21 +5,36 @@ type Option func(*Request)
 // WithTopP 设置请求中的 TopP 参数，用于控制生成文本的多样性
 func WithTopP(top_p float32) Option {
 	return func(r *Request) {
-		r.TopP = top_p // 将 TopP 参数设置到请求对象中
+		r.TopP = &top_p // 将 TopP 参数设置到请求对象中
 	}
 }
 
 // WithFrequencyPenalty 设置请求中的 FrequencyPenalty 参数，用于惩罚频繁出现的词
 func WithFrequencyPenalty(frequencyPenalty float32) Option {
 	return func(r *Request) {
-		r.FrequencyPenalty = frequencyPenalty // 将 FrequencyPenalty 参数设置到请求对象中
+		r.FrequencyPenalty = &frequencyPenalty // 将 FrequencyPenalty 参数设置到请求对象中
 	}
 }
 
 // WithPresencePenalty 设置请求中的 PresencePenalty 参数，用于惩罚过于新颖的词
 func WithPresencePenalty(presencePenalty float32) Option {
 	return func(r *Request) {
-		r.PresencePenalty = presencePenalty // 将 PresencePenalty 参数设置到请求对象中
+		r.PresencePenalty = &presencePenalty // 将 PresencePenalty 参数设置到请求对象中
+	}
+}
+
+// WithTemperature 设置请求中的 Temperature 参数，用于控制生成文本的随机性。
+// 设置为 0 时会显式发送 0，以获得尽可能确定的输出。
+func WithTemperature(temperature float32) Option {
+	return func(r *Request) {
+		r.Temperature = &temperature // 将 Temperature 参数设置到请求对象中
+	}
+}
+
+// WithMaxTokens 设置请求中的 MaxTokens 参数，用于限制最大生成标记数
+func WithMaxTokens(maxTokens int) Option {
+	return func(r *Request) {
+		r.MaxTokens = &maxTokens // 将 MaxTokens 参数设置到请求对象中
 	}
 }
 
@@ -61,3 +76,58 @@ func WithEndpoint(endpoint string) Option {
 		r.Endpoint = endpoint // 将传入的端点字符串赋值给 Request 的 Endpoint 字段。
 	}
 }
+
+// WithTools 设置请求中的 Tools 参数，用于提供模型可以调用的工具
+func WithTools(tools []Tool) Option {
+	return func(r *Request) {
+		r.Tools = tools // 将 Tools 参数设置到请求对象中
+	}
+}
+
+// WithToolChoice 设置请求中的 ToolChoice 参数，用于控制模型调用工具的方式
+func WithToolChoice(choice any) Option {
+	return func(r *Request) {
+		r.ToolChoice = choice // 将 ToolChoice 参数设置到请求对象中
+	}
+}
+
+// WithRequestID 设置请求中的 RequestID 参数，用于标识本次请求
+func WithRequestID(id string) Option {
+	return func(r *Request) {
+		r.RequestID = id // 将 RequestID 参数设置到请求对象中
+	}
+}
+
+// WithReasoningEffort 设置请求中的 ReasoningEffort 参数，用于控制推理模型的思考强度
+func WithReasoningEffort(effort string) Option {
+	return func(r *Request) {
+		r.ReasoningEffort = effort // 将 ReasoningEffort 参数设置到请求对象中
+	}
+}
+
+// WithMaxCompletionTokens 设置请求中的 MaxCompletionTokens 参数，用于限制推理模型的最大生成标记数
+func WithMaxCompletionTokens(maxCompletionTokens int) Option {
+	return func(r *Request) {
+		r.MaxCompletionTokens = &maxCompletionTokens // 将 MaxCompletionTokens 参数设置到请求对象中
+	}
+}
+
+// WithResponseFormat 设置请求中的 ResponseFormat 参数，用于指定模型输出的格式
+func WithResponseFormat(format *ResponseFormat) Option {
+	return func(r *Request) {
+		r.ResponseFormat = format // 将 ResponseFormat 参数设置到请求对象中
+	}
+}
+
+// WithJSONMode 开启 JSON 模式，要求模型输出合法的 JSON 对象
+func WithJSONMode() Option {
+	return WithResponseFormat(&ResponseFormat{Type: ResponseFormatJSONObject})
+}
+
+// WithJSONSchema 开启 JSON Schema 模式，要求模型输出符合 schema 的 JSON 对象
+func WithJSONSchema(name string, schema any, strict bool) Option {
+	return WithResponseFormat(&ResponseFormat{
+		Type:       ResponseFormatJSONSchema,
+		JSONSchema: &JSONSchema{Name: name, Schema: schema, Strict: strict},
+	})
+}
@@ -3,29 +3,87 @@ package request
 import (
 	"bytes"
 	"io"
+	"strings"
 
 	"github.com/bytedance/sonic"
 )
 
 // Request 结构体定义了一个请求的参数
 type Request struct {
-	TopP             float32    `json:"top_p,omitempty"`             // TopP 是一个浮点数，表示选择的概率阈值
-	FrequencyPenalty float32    `json:"frequency_penalty,omitempty"` // FrequencyPenalty 是一个整数，用于对高频词汇进行惩罚
-	PresencePenalty  float32    `json:"presence_penalty,omitempty"`  // PresencePenalty 是一个整数，用于对存在的词汇进行惩罚
-	Temperature      float32    `json:"temperature,omitempty"`       // 使用什么采样温度，介于 0 和 2 之间。较高的值（如 0.8）将使输出更加随机，而较低的值（如 0.2）将使输出更加集中和确定。 我们通常建议改变这个或top_p但不是两者。
-	MaxTokens        int        `json:"max_tokens,omitempty"`        // 最大生成标记数
-	Model            string     `json:"model"`                       // Model 是一个字符串，指定了要使用的模型
-	Stop             []string   `json:"stop,omitempty"`              // Stop 是一个字符串切片，包含需要过滤停止的词汇
-	Messages         []Messages `json:"messages,omitempty"`          // Messages 是一个消息切片，包含了请求中的消息内容
-	Stream           bool       `json:"stream,omitempty"`            // 默认为 false 如果设置,则像在 ChatGPT 中一样会发送部分消息增量。标记将以仅数据的服务器发送事件的形式发送,这些事件在可用时,并在 data: [DONE] 消息终止流。Python 代码示例。
-	Endpoint         string     `json:"-"`                           // EndPoint 是一个字符串，表示请求的端点
-	ChannelMaxLength int        `json:"-"`                           // ChannelMaxLength 是一个整数，表示通道的最大长度
+	TopP                *float32        `json:"top_p,omitempty"`                 // TopP 是一个浮点数，表示选择的概率阈值，为 nil 时使用服务商的默认值
+	FrequencyPenalty    *float32        `json:"frequency_penalty,omitempty"`     // FrequencyPenalty 是一个浮点数，用于对高频词汇进行惩罚，为 nil 时不发送
+	PresencePenalty     *float32        `json:"presence_penalty,omitempty"`      // PresencePenalty 是一个浮点数，用于对存在的词汇进行惩罚，为 nil 时不发送
+	Temperature         *float32        `json:"temperature,omitempty"`           // 使用什么采样温度，介于 0 和 2 之间。较高的值（如 0.8）将使输出更加随机，而较低的值（如 0.2）将使输出更加集中和确定。 我们通常建议改变这个或top_p但不是两者。为 nil 时使用服务商的默认值，设置为 0 时会显式发送 0。
+	MaxTokens           *int            `json:"max_tokens,omitempty"`            // 最大生成标记数，为 nil 时使用服务商的默认值
+	MaxCompletionTokens *int            `json:"max_completion_tokens,omitempty"` // 最大生成标记数，推理模型中包含思考过程消耗的标记数
+	ReasoningEffort     string          `json:"reasoning_effort,omitempty"`      // 推理模型的思考强度，可选 low、medium、high
+	Model               string          `json:"model"`                           // Model 是一个字符串，指定了要使用的模型
+	Stop                []string        `json:"stop,omitempty"`                  // Stop 是一个字符串切片，包含需要过滤停止的词汇
+	Messages            []Messages      `json:"messages,omitempty"`              // Messages 是一个消息切片，包含了请求中的消息内容
+	Stream              bool            `json:"stream,omitempty"`                // 默认为 false 如果设置,则像在 ChatGPT 中一样会发送部分消息增量。标记将以仅数据的服务器发送事件的形式发送,这些事件在可用时,并在 data: [DONE] 消息终止流。Python 代码示例。
+	Tools               []Tool          `json:"tools,omitempty"`                 // Tools 是模型可以调用的工具列表，如函数调用、联网搜索等
+	ToolChoice          any             `json:"tool_choice,omitempty"`           // ToolChoice 控制模型调用工具的方式，可以是 auto、none 或指定的函数
+	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`       // ResponseFormat 指定模型输出的格式，如 JSON 模式或 JSON Schema
+	RequestID           string          `json:"request_id,omitempty"`            // RequestID 是由调用方传入的请求唯一标识，部分服务商（如智谱）会原样返回
+	Endpoint            string          `json:"-"`                               // EndPoint 是一个字符串，表示请求的端点
+	ChannelMaxLength    int             `json:"-"`                               // ChannelMaxLength 是一个整数，表示通道的最大长度
 }
 
 // Messages 结构体用于表示一个消息，包含内容和角色信息
 type Messages struct {
-	Role    string `json:"role"`    // Role 字段表示消息的角色，例如发送者、接收者等
-	Content string `json:"content"` // Content 字段表示消息的内容
+	Role             string     `json:"role"`                        // Role 字段表示消息的角色，例如发送者、接收者等
+	Content          string     `json:"content"`                     // Content 字段表示消息的内容
+	ReasoningContent string     `json:"reasoning_content,omitempty"` // ReasoningContent 字段表示推理模型输出的思考过程
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`        // ToolCalls 字段表示助手消息中模型发起的工具调用
+	ToolCallID       string     `json:"tool_call_id,omitempty"`      // ToolCallID 字段表示工具消息对应的工具调用标识
+}
+
+// Tool 结构体定义了模型可以调用的工具
+type Tool struct {
+	Type      string     `json:"type"`                 // Type 字段表示工具类型，如 function、web_search
+	Function  *Function  `json:"function,omitempty"`   // Function 字段表示函数工具的定义
+	WebSearch *WebSearch `json:"web_search,omitempty"` // WebSearch 字段表示联网搜索工具的配置
+}
+
+// Function 结构体定义了一个可供模型调用的函数
+type Function struct {
+	Name        string `json:"name"`                  // Name 字段表示函数名称
+	Description string `json:"description,omitempty"` // Description 字段表示函数的用途描述
+	Parameters  any    `json:"parameters,omitempty"`  // Parameters 字段表示函数参数的 JSON Schema
+}
+
+// WebSearch 结构体定义了联网搜索工具的配置
+type WebSearch struct {
+	Enable       bool   `json:"enable"`                  // Enable 字段表示是否启用联网搜索
+	SearchQuery  string `json:"search_query,omitempty"`  // SearchQuery 字段表示强制使用的搜索关键词
+	SearchResult bool   `json:"search_result,omitempty"` // SearchResult 字段表示是否在响应中返回搜索结果
+}
+
+// ResponseFormat 结构体定义了模型输出的格式
+type ResponseFormat struct {
+	Type       string      `json:"type"`                  // Type 字段表示输出格式，可选 text、json_object、json_schema
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"` // JSONSchema 字段表示 json_schema 模式下输出需要符合的 Schema
+}
+
+// JSONSchema 结构体定义了 json_schema 模式下的 Schema 信息
+type JSONSchema struct {
+	Name        string `json:"name"`                  // Name 字段表示 Schema 的名称
+	Description string `json:"description,omitempty"` // Description 字段表示 Schema 的描述
+	Schema      any    `json:"schema"`                // Schema 字段表示 JSON Schema 定义
+	Strict      bool   `json:"strict,omitempty"`      // Strict 字段表示是否严格遵循 Schema
+}
+
+// ToolCall 结构体表示助手消息中的一次工具调用
+type ToolCall struct {
+	ID       string       `json:"id"`       // ID 字段表示工具调用的唯一标识
+	Type     string       `json:"type"`     // Type 字段表示工具类型，目前为 function
+	Function FunctionCall `json:"function"` // Function 字段表示被调用的函数及其参数
+}
+
+// FunctionCall 结构体表示一次函数调用的名称与参数
+type FunctionCall struct {
+	Name      string `json:"name"`      // Name 字段表示被调用的函数名称
+	Arguments string `json:"arguments"` // Arguments 字段表示 JSON 格式的函数参数
 }
 
 // NewRequest 创建并返回一个新的Request实例。
@@ -33,11 +91,8 @@ type Messages struct {
 // 这种方法允许灵活的配置，而不必直接在结构体初始化时指定所有参数。
 func NewRequest(opts ...func(*Request)) *Request {
 	// 初始化Request结构体，默认设置了一些基本参数。
+	// 采样参数默认不设置，由服务商使用各自的默认值，只有调用方显式设置的参数才会被发送。
 	resp := &Request{
-		TopP:             1,            // 设置默认的 TopP 值为 0.8
-		FrequencyPenalty: 0,            // 设置默认的 FrequencyPenalty 值为 0
-		PresencePenalty:  0,            // 设置默认的 PresencePenalty 值为 0
-		Temperature:      0,            // 设置默认的 Temperature 值为 0
 		Stop:             []string{},   // 设置默认的 Stop 切片为空
 		Messages:         []Messages{}, // 设置默认的 Messages 切片为空
 		Model:            "",           // 设置默认的 Model 值为空字符串
@@ -54,6 +109,11 @@ func NewRequest(opts ...func(*Request)) *Request {
 	return resp
 }
 
+// Ptr 返回指向 v 的指针，便于直接设置 TopP、Temperature 等可选的采样参数。
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 // MarshalToString 将请求对象序列化为字符串。
 //
 // 本方法利用了全局默认配置的sonic配置实例，对当前请求对象进行序列化。
@@ -103,6 +163,31 @@ func NewMessage(role, content string) Messages {
 	return Messages{Role: role, Content: content}
 }
 
+// NewToolMessage 创建一个表示工具执行结果的消息对象。
+// 参数:
+//
+//	toolCallID - 对应的工具调用标识。
+//	content - 工具执行的结果。
+//
+// 返回值:
+//
+//	Messages - 角色为 tool 的消息结构体。
+func NewToolMessage(toolCallID, content string) Messages {
+	return Messages{Role: MessageRoleTool, Content: content, ToolCallID: toolCallID}
+}
+
+// NewFunctionTool 创建一个函数类型的工具定义。
+// 参数 parameters 为函数参数的 JSON Schema。
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{Type: ToolTypeFunction, Function: &Function{Name: name, Description: description, Parameters: parameters}}
+}
+
+// NewWebSearchTool 创建一个联网搜索类型的工具定义。
+// 参数 query 为空时由模型自行决定搜索关键词。
+func NewWebSearchTool(query string, withResult bool) Tool {
+	return Tool{Type: ToolTypeWebSearch, WebSearch: &WebSearch{Enable: true, SearchQuery: query, SearchResult: withResult}}
+}
+
 // NewSystemMessage 创建一个系统消息对象。
 // 这个函数用于封装一个具有系统角色的消息，方便后续处理和分发。
 // 参数:
@@ -142,3 +227,64 @@ func NewUserMessage(content string) Messages {
 func NewAssistantMessage(content string) Messages {
 	return Messages{Role: MessageRoleAssistant, Content: content}
 }
+
+// StripReasoning 返回去除了思考过程的消息副本。
+// 多数推理模型（如 DeepSeek-R1）不接受历史消息中携带思考过程，回放历史消息前应调用该函数。
+// 它会清空 ReasoningContent 字段，并移除助手消息内容开头以 <think></think> 包裹的思考过程。
+func StripReasoning(messages []Messages) []Messages {
+	out := make([]Messages, len(messages))
+	for i, msg := range messages {
+		msg.ReasoningContent = ""
+		if msg.Role == MessageRoleAssistant {
+			msg.Content = stripThink(msg.Content)
+		}
+		out[i] = msg
+	}
+	return out
+}
+
+// stripThink 移除内容开头以 <think></think> 包裹的思考过程
+func stripThink(content string) string {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "<think>") {
+		return content
+	}
+
+	end := strings.Index(trimmed, "</think>")
+	if end < 0 {
+		return content
+	}
+	return strings.TrimLeft(trimmed[end+len("</think>"):], " \t\r\n")
+}
+
+// EmulateResponseFormat 返回以提示词模拟输出格式的请求副本，用于不支持 response_format 的服务商。
+// 它会将输出格式的要求追加到系统消息中（没有系统消息时新增一条），
+// 参数 jsonObject 为 true 时保留 json_object 模式，否则移除 response_format 参数。
+func EmulateResponseFormat(in Request, jsonObject bool) Request {
+	format := in.ResponseFormat
+	if format == nil || format.Type == ResponseFormatText {
+		return in
+	}
+
+	instruction := "请仅输出一个合法的 JSON 对象，不要输出任何其他内容，也不要使用 Markdown 代码块。"
+	if format.Type == ResponseFormatJSONSchema && format.JSONSchema != nil {
+		schema, _ := sonic.ConfigDefault.MarshalToString(format.JSONSchema.Schema)
+		instruction = "请仅输出一个符合以下 JSON Schema 的 JSON 对象，不要输出任何其他内容，也不要使用 Markdown 代码块。\nJSON Schema：\n" + schema
+	}
+
+	messages := make([]Messages, 0, len(in.Messages)+1)
+	if len(in.Messages) > 0 && in.Messages[0].Role == MessageRoleSystem {
+		system := in.Messages[0]
+		system.Content = strings.TrimSpace(system.Content + "\n\n" + instruction)
+		messages = append(append(messages, system), in.Messages[1:]...)
+	} else {
+		messages = append(append(messages, NewSystemMessage(instruction)), in.Messages...)
+	}
+	in.Messages = messages
+
+	in.ResponseFormat = nil
+	if jsonObject {
+		in.ResponseFormat = &ResponseFormat{Type: ResponseFormatJSONObject}
+	}
+	return in
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_StripReasoning(t *testing.T) {
+	messages := []Messages{
+		NewUserMessage("<think>用户消息不处理</think>你好"),
+		{Role: MessageRoleAssistant, Content: "\n<think>先想一想</think>\n\n你好！", ReasoningContent: "先想一想"},
+		{Role: MessageRoleAssistant, Content: "没有思考过程", ReasoningContent: "思考"},
+	}
+
+	got := StripReasoning(messages)
+	want := []string{"<think>用户消息不处理</think>你好", "你好！", "没有思考过程"}
+	for i, msg := range got {
+		if msg.Content != want[i] || msg.ReasoningContent != "" {
+			t.Fatalf("messages[%d] = %+v, want content %q", i, msg, want[i])
+		}
+	}
+
+	if messages[1].ReasoningContent == "" {
+		t.Fatal("StripReasoning should not modify the input messages")
+	}
+}
+
+func Test_EmulateResponseFormat(t *testing.T) {
+	in := *NewRequest(
+		WithMessages([]Messages{NewUserMessage("你好")}),
+		WithJSONSchema("poem", map[string]any{"type": "object"}, true),
+	)
+
+	out := EmulateResponseFormat(in, false)
+	if out.ResponseFormat != nil || len(out.Messages) != 2 || out.Messages[0].Role != MessageRoleSystem {
+		t.Fatalf("unexpected emulated request: %+v", out)
+	}
+	if len(in.Messages) != 1 {
+		t.Fatal("the original request should not be modified")
+	}
+}
+
+func Test_SamplingParams(t *testing.T) {
+	payload, err := NewRequest(WithModel("m")).MarshalToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"top_p", "temperature", "max_tokens", "frequency_penalty"} {
+		if strings.Contains(payload, key) {
+			t.Fatalf("unset %s should not be sent: %s", key, payload)
+		}
+	}
+
+	payload, _ = NewRequest(WithTemperature(0), WithMaxTokens(0), WithTopP(0.5)).MarshalToString()
+	for _, want := range []string{`"temperature":0`, `"max_tokens":0`, `"top_p":0.5`} {
+		if !strings.Contains(payload, want) {
+			t.Fatalf("explicit value %s should be sent: %s", want, payload)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package response
+
+import "sort"
+
+// Accumulator 用于将流式响应的分片归并为一个完整的响应。
+// 它会拼接每个选项的内容、思考过程与工具调用参数，并保留最后一次出现的结束原因与用量统计。
+// 非流式响应也可以直接传入，此时归并结果即为该响应本身。
+type Accumulator struct {
+	resp    Response
+	choices map[int]*choiceState
+	err     error
+}
+
+// choiceState 保存单个选项的归并状态
+type choiceState struct {
+	finishReason string
+	message      Message
+	calls        map[int]int // 工具调用序号到 message.ToolCalls 下标的映射
+}
+
+// NewAccumulator 创建一个新的 Accumulator 实例
+func NewAccumulator() *Accumulator {
+	return &Accumulator{choices: make(map[int]*choiceState)}
+}
+
+// Add 将一个响应分片归并到结果中
+func (a *Accumulator) Add(chunk Response) {
+	if chunk.Err != nil {
+		a.err = chunk.Err
+	}
+	if chunk.ID != "" {
+		a.resp.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.resp.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != nil {
+		a.resp.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.RequestID != "" {
+		a.resp.RequestID = chunk.RequestID
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = chunk.Usage
+	}
+	a.resp.WebSearch = append(a.resp.WebSearch, chunk.WebSearch...)
+
+	for _, c := range chunk.Choices {
+		state, ok := a.choices[c.Index]
+		if !ok {
+			state = &choiceState{calls: make(map[int]int)}
+			a.choices[c.Index] = state
+		}
+
+		if c.FinishReason != "" {
+			state.finishReason = c.FinishReason
+		}
+		if c.Message != nil {
+			state.message = *c.Message
+		}
+		if c.Delta != nil {
+			state.addDelta(*c.Delta)
+		}
+	}
+}
+
+// addDelta 将增量内容拼接到选项的消息中
+func (s *choiceState) addDelta(delta Delta) {
+	if delta.Role != "" {
+		s.message.Role = delta.Role
+	}
+	s.message.Content += delta.Content
+	s.message.ReasoningContent += delta.ReasoningContent
+
+	for _, call := range delta.ToolCalls {
+		i, ok := s.calls[call.Index]
+		// 部分服务商并行调用时序号相同但标识不同，此时视为一次新的调用。
+		if ok && call.ID != "" && s.message.ToolCalls[i].ID != "" && s.message.ToolCalls[i].ID != call.ID {
+			ok = false
+		}
+		if !ok {
+			s.calls[call.Index] = len(s.message.ToolCalls)
+			s.message.ToolCalls = append(s.message.ToolCalls, ToolCall{Index: call.Index})
+			i = s.calls[call.Index]
+		}
+
+		target := &s.message.ToolCalls[i]
+		if call.ID != "" {
+			target.ID = call.ID
+		}
+		if call.Type != "" {
+			target.Type = call.Type
+		}
+		if call.Function.Name != "" {
+			target.Function.Name = call.Function.Name
+		}
+		target.Function.Arguments += call.Function.Arguments
+	}
+}
+
+// Response 返回归并后的完整响应，每个选项的内容都位于 Message 字段中
+func (a *Accumulator) Response() Response {
+	resp := a.resp
+	resp.Object = "chat.completion"
+	resp.Choices = make([]Choices, 0, len(a.choices))
+
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	for _, index := range indexes {
+		state := a.choices[index]
+		msg := state.message
+		msg.ToolCalls = append([]ToolCall(nil), msg.ToolCalls...)
+		if msg.Role == "" {
+			msg.Role = "assistant"
+		}
+		resp.Choices = append(resp.Choices, Choices{Index: index, FinishReason: state.finishReason, Message: &msg})
+	}
+	return resp
+}
+
+// Message 返回第一个选项归并后的消息
+func (a *Accumulator) Message() Message {
+	resp := a.Response()
+	if len(resp.Choices) == 0 {
+		return Message{Role: "assistant"}
+	}
+	return *resp.Choices[0].Message
+}
+
+// FinishReason 返回第一个选项的结束原因
+func (a *Accumulator) FinishReason() string {
+	resp := a.Response()
+	if len(resp.Choices) == 0 {
+		return ""
+	}
+	return resp.Choices[0].FinishReason
+}
+
+// Usage 返回最后一次收到的用量统计，未收到时返回 nil
+func (a *Accumulator) Usage() *Usage {
+	return a.resp.Usage
+}
+
+// Err 返回响应过程中出现的错误，没有错误时返回 nil。
+// 出现错误时已归并的内容是不完整的。
+func (a *Accumulator) Err() error {
+	return a.err
+}
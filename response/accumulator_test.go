@@ -0,0 +1,28 @@
+package response
+
+import "testing"
+
+func Test_Accumulator(t *testing.T) {
+	chunks := []Response{
+		{ID: "1", Choices: []Choices{{Delta: &Delta{Role: "assistant", ReasoningContent: "想"}}}},
+		{Choices: []Choices{{Delta: &Delta{Content: "你", ToolCalls: []ToolCall{{Index: 0, ID: "call_1", Type: "function", Function: FunctionCall{Name: "weather", Arguments: `{"city":`}}}}}}},
+		{Choices: []Choices{{Delta: &Delta{Content: "好", ToolCalls: []ToolCall{{Index: 0, Function: FunctionCall{Arguments: `"北京"}`}}}}}}},
+		{Choices: []Choices{{FinishReason: "tool_calls", Delta: &Delta{}}}, Usage: &Usage{TotalTokens: 10}},
+	}
+
+	acc := NewAccumulator()
+	for _, chunk := range chunks {
+		acc.Add(chunk)
+	}
+
+	msg := acc.Message()
+	if msg.Role != "assistant" || msg.Content != "你好" || msg.ReasoningContent != "想" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Arguments != `{"city":"北京"}` || msg.ToolCalls[0].ID != "call_1" {
+		t.Fatalf("unexpected tool calls: %+v", msg.ToolCalls)
+	}
+	if acc.FinishReason() != "tool_calls" || acc.Usage().TotalTokens != 10 || acc.Response().ID != "1" {
+		t.Fatal("finish reason, usage and id should be kept")
+	}
+}
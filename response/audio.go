@@ -0,0 +1,26 @@
+package response
+
+// TranscriptionResponse 结构体定义了语音转文字接口的响应。
+// 当请求的格式为 text、srt 或 vtt 时，服务商返回的原始文本保存在 Text 字段中。
+type TranscriptionResponse struct {
+	Text     string                 `json:"text"`               // 识别出的文本
+	Language string                 `json:"language,omitempty"` // 音频的语言，仅 verbose_json 格式返回
+	Duration float64                `json:"duration,omitempty"` // 音频的时长，单位为秒，仅 verbose_json 格式返回
+	Segments []TranscriptionSegment `json:"segments,omitempty"` // 分段信息，仅 verbose_json 格式返回
+	Words    []TranscriptionWord    `json:"words,omitempty"`    // 逐词的时间戳，仅 verbose_json 格式且请求 word 粒度时返回
+}
+
+// TranscriptionSegment 结构体定义了一段识别结果
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`    // 分段的序号
+	Start float64 `json:"start"` // 分段的开始时间，单位为秒
+	End   float64 `json:"end"`   // 分段的结束时间，单位为秒
+	Text  string  `json:"text"`  // 分段的文本
+}
+
+// TranscriptionWord 结构体定义了一个词的时间戳
+type TranscriptionWord struct {
+	Word  string  `json:"word"`  // 词的文本
+	Start float64 `json:"start"` // 词的开始时间，单位为秒
+	End   float64 `json:"end"`   // 词的结束时间，单位为秒
+}
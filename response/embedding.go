@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+)
+
+// EmbeddingResponse 结构体定义了向量化接口的响应
+type EmbeddingResponse struct {
+	Object string      `json:"object"` // 响应对象的类型，固定为 list
+	Data   []Embedding `json:"data"`   // 向量列表，顺序与请求中的文本一致
+	Model  string      `json:"model"`  // 使用的模型名称
+	Usage  *Usage      `json:"usage"`  // 使用情况统计
+}
+
+// Embedding 结构体定义了一条文本的向量
+type Embedding struct {
+	Object    string `json:"object"`    // 对象类型，固定为 embedding
+	Index     int    `json:"index"`     // 对应文本在请求中的序号
+	Embedding Vector `json:"embedding"` // 向量
+}
+
+// Vector 表示一个向量。
+// 反序列化时同时支持浮点数数组与 base64 编码（小端序 float32）两种格式。
+type Vector []float32
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，自动解码 base64 格式的向量
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '"' {
+		var values []float32
+		if err := sonic.ConfigDefault.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		*v = values
+		return nil
+	}
+
+	var encoded string
+	if err := sonic.ConfigDefault.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	if len(raw)%4 != 0 {
+		return errors.New("invalid base64 embedding length")
+	}
+
+	values := make([]float32, len(raw)/4)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	*v = values
+	return nil
+}
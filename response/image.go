@@ -0,0 +1,15 @@
+package response
+
+// ImageResponse 结构体定义了图像生成接口的响应
+type ImageResponse struct {
+	Created int     `json:"created"`           // 响应创建的时间戳
+	Data    []Image `json:"data"`              // 生成的图像列表
+	TaskID  string  `json:"task_id,omitempty"` // 异步任务的 ID，仅异步接口返回
+}
+
+// Image 结构体定义了一张生成的图像
+type Image struct {
+	URL           string `json:"url,omitempty"`            // 图像的地址
+	B64JSON       string `json:"b64_json,omitempty"`       // base64 编码的图像内容
+	RevisedPrompt string `json:"revised_prompt,omitempty"` // 服务商改写后的图像描述
+}
@@ -0,0 +1,15 @@
+package response
+
+// ModelList 结构体定义了模型列表接口的响应
+type ModelList struct {
+	Object string  `json:"object"` // 响应对象的类型，固定为 list
+	Data   []Model `json:"data"`   // 模型列表
+}
+
+// Model 结构体定义了一个可用的模型
+type Model struct {
+	ID      string `json:"id"`       // 模型名称
+	Object  string `json:"object"`   // 对象类型，固定为 model
+	Created int    `json:"created"`  // 模型创建的时间戳
+	OwnedBy string `json:"owned_by"` // 模型的所有者
+}
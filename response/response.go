@@ -2,13 +2,19 @@ package response
 
 // Response 结构体定义了API响应的数据结构
 type Response struct {
-	ID                string    `json:"id"`                 // 请求的唯一标识符
-	Object            string    `json:"object"`             // 响应对象的类型
-	Created           int       `json:"created"`            // 响应创建的时间戳
-	Model             string    `json:"model"`              // 使用的模型名称
-	SystemFingerprint any       `json:"system_fingerprint"` // 系统指纹，用于识别请求来源
-	Choices           []Choices `json:"choices"`            // 选项列表，包含用户的选择信息
-	Usage             *Usage    `json:"usage"`              // 使用情况统计，包括使用的token数量
+	ID                string      `json:"id"`                   // 请求的唯一标识符
+	Object            string      `json:"object"`               // 响应对象的类型
+	Created           int         `json:"created"`              // 响应创建的时间戳
+	Model             string      `json:"model"`                // 使用的模型名称
+	SystemFingerprint any         `json:"system_fingerprint"`   // 系统指纹，用于识别请求来源
+	Choices           []Choices   `json:"choices"`              // 选项列表，包含用户的选择信息
+	Usage             *Usage      `json:"usage"`                // 使用情况统计，包括使用的token数量
+	RequestID         string      `json:"request_id,omitempty"` // 服务商返回的请求标识，如智谱的 request_id
+	WebSearch         []WebSearch `json:"web_search,omitempty"` // 联网搜索工具返回的搜索结果
+
+	// Err 字段表示响应过程中出现的错误，如格式错误的分片、服务商返回的错误事件、空闲超时或被截断的流。
+	// 携带错误的响应总是通道中的最后一个响应，其余字段为空。
+	Err error `json:"-"`
 }
 
 // Usage 结构体定义了API的使用统计信息
@@ -16,6 +22,13 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`     // 提示部分使用的token数量
 	CompletionTokens int `json:"completion_tokens"` // 完成部分使用的token数量
 	TotalTokens      int `json:"total_tokens"`      // 总共使用的token数量
+
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"` // 完成部分token数量的明细
+}
+
+// CompletionTokensDetails 结构体定义了完成部分token数量的明细
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"` // 推理模型思考过程使用的token数量
 }
 
 // Choices 结构体定义了用户选择的详细信息
@@ -28,13 +41,42 @@ type Choices struct {
 
 // Message 结构体定义了消息的内容和角色
 type Message struct {
-	Role    string `json:"role"`    // 消息的角色，如提示、回答等
-	Content string `json:"content"` // 消息的实际内容
+	Role             string     `json:"role"`                        // 消息的角色，如提示、回答等
+	Content          string     `json:"content"`                     // 消息的实际内容
+	ReasoningContent string     `json:"reasoning_content,omitempty"` // 推理模型输出的思考过程，与最终回答分开返回
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`        // 模型发起的工具调用
 }
 
 // Delta 表示一个内容差异的结构体。
 // 它用于存储两个版本之间内容的差异，通常用于版本控制系统或编辑器中。
 // Content 字段存储了具体的差异内容，以文本形式表示。
 type Delta struct {
-	Content string `json:"content"`
+	Role             string     `json:"role,omitempty"`
+	Content          string     `json:"content"`
+	ReasoningContent string     `json:"reasoning_content,omitempty"`
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall 结构体定义了模型发起的一次工具调用。
+// 流式响应中同一次调用会被拆分到多个分片，Index 字段用于将分片归并到同一次调用。
+type ToolCall struct {
+	Index    int          `json:"index"`    // 工具调用在本次回复中的序号
+	ID       string       `json:"id"`       // 工具调用的唯一标识
+	Type     string       `json:"type"`     // 工具类型，目前为 function
+	Function FunctionCall `json:"function"` // 被调用的函数及其参数
+}
+
+// FunctionCall 结构体定义了被调用的函数名称与参数
+type FunctionCall struct {
+	Name      string `json:"name"`      // 函数名称
+	Arguments string `json:"arguments"` // JSON 格式的函数参数，流式响应中为增量片段
+}
+
+// WebSearch 结构体定义了联网搜索返回的一条结果
+type WebSearch struct {
+	Icon    string `json:"icon,omitempty"`    // 来源网站的图标
+	Title   string `json:"title,omitempty"`   // 搜索结果的标题
+	Link    string `json:"link,omitempty"`    // 搜索结果的链接
+	Media   string `json:"media,omitempty"`   // 搜索结果的来源媒体
+	Content string `json:"content,omitempty"` // 搜索结果的摘要内容
 }
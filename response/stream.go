@@ -0,0 +1,44 @@
+package response
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Stream 是补全结果的流，它在通道之外提供了明确的 Close 方法。
+// 调用方不再需要剩余的结果时应调用 Close，适配器会立即关闭连接并结束输出协程，
+// 而不必等待父级上下文结束。
+type Stream struct {
+	C      <-chan Response // 补全结果的通道，流结束或被关闭后通道会被关闭
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewStream 使用补全结果的通道与取消该请求的函数创建一个 Stream
+func NewStream(c <-chan Response, cancel context.CancelFunc) *Stream {
+	return &Stream{C: c, cancel: cancel}
+}
+
+// Recv 返回下一个结果。流正常结束时返回 io.EOF，流因错误结束时返回该错误。
+func (s *Stream) Recv() (Response, error) {
+	resp, ok := <-s.C
+	if !ok {
+		return Response{}, io.EOF
+	}
+	if resp.Err != nil {
+		return Response{}, resp.Err
+	}
+	return resp, nil
+}
+
+// Close 取消请求并丢弃尚未读取的结果，返回时连接已关闭、输出协程已退出。
+// Close 可以被多次调用，也可以在读取完毕后调用。
+func (s *Stream) Close() error {
+	s.once.Do(func() {
+		s.cancel()
+		for range s.C {
+		}
+	})
+	return nil
+}
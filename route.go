@@ -0,0 +1,82 @@
+package uniai
+
+import (
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/request"
+)
+
+// route 根据请求中的模型名称查找别名，返回处理该请求的实例与别名的目标。
+// 没有命中别名时返回 u 本身；别名没有设置客户端选项时同样由 u 处理，否则由按别名缓存的新实例处理，
+// 新实例的选项由 client.Options.Derive 在 u 的选项之上应用别名的选项得到。
+// 别名只解析一次，目标模型不会再被当作别名解析，因此别名之间不会形成循环。
+func (u *uniai) route(model string) (*uniai, client.Route, bool) {
+	route, ok := u.opts.Routes[model]
+	if !ok {
+		return u, client.Route{}, false
+	}
+	if len(route.Options) == 0 {
+		return u, route, true
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	target, ok := u.targets[model]
+	if !ok {
+		if u.targets == nil {
+			u.targets = make(map[string]*uniai)
+		}
+		target = &uniai{opts: u.opts.Derive(route.Options...)}
+		u.targets[model] = target
+	}
+	return target, route, true
+}
+
+// withDefaults 使用 params 设置的参数填充请求中未设置的参数，请求中已设置的参数保持不变
+func withDefaults(in request.Request, params []request.Option) request.Request {
+	if len(params) == 0 {
+		return in
+	}
+
+	var d request.Request
+	for _, opt := range params {
+		opt(&d)
+	}
+
+	if in.TopP == nil {
+		in.TopP = d.TopP
+	}
+	if in.FrequencyPenalty == nil {
+		in.FrequencyPenalty = d.FrequencyPenalty
+	}
+	if in.PresencePenalty == nil {
+		in.PresencePenalty = d.PresencePenalty
+	}
+	if in.Temperature == nil {
+		in.Temperature = d.Temperature
+	}
+	if in.MaxTokens == nil {
+		in.MaxTokens = d.MaxTokens
+	}
+	if in.MaxCompletionTokens == nil {
+		in.MaxCompletionTokens = d.MaxCompletionTokens
+	}
+	if in.ReasoningEffort == "" {
+		in.ReasoningEffort = d.ReasoningEffort
+	}
+	if len(in.Stop) == 0 {
+		in.Stop = d.Stop
+	}
+	if len(in.Tools) == 0 {
+		in.Tools = d.Tools
+	}
+	if in.ToolChoice == nil {
+		in.ToolChoice = d.ToolChoice
+	}
+	if in.ResponseFormat == nil {
+		in.ResponseFormat = d.ResponseFormat
+	}
+	if in.Endpoint == "" {
+		in.Endpoint = d.Endpoint
+	}
+	return in
+}
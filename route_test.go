@@ -0,0 +1,131 @@
+package uniai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/uniaitest"
+)
+
+func Test_Routes(t *testing.T) {
+	base := uniaitest.NewClient(uniaitest.Text("a"), uniaitest.Text("b"))
+	other := uniaitest.NewClient(uniaitest.Text("c"), uniaitest.Text("d"))
+	u := New(
+		client.WithClient(base),
+		client.WithRoute("fast", client.Route{
+			Options: []client.Option{client.WithType(client.Tongyi), client.WithClient(other)},
+			Model:   "qwen-turbo",
+			Params:  []request.Option{request.WithTemperature(0.2), request.WithMaxTokens(256)},
+		}),
+		client.WithRoutes(map[string]client.Route{"smart": {Model: "gpt-4o"}, "embed": {Options: []client.Option{client.WithClient(other)}, Model: "text-embedding-v3"}}),
+	)
+
+	send := func(model string, opts ...request.Option) {
+		t.Helper()
+		in := *request.NewRequest(request.WithModel(model))
+		for _, opt := range opts {
+			opt(&in)
+		}
+		out, err := u.Completions(context.Background(), in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range out {
+		}
+	}
+	send("fast")
+	send("fast", request.WithTemperature(0.9))
+	send("smart")
+	send("gpt-4o-mini")
+
+	requests := other.Requests()
+	if len(requests) != 2 || requests[0].Model != "qwen-turbo" || *requests[0].Temperature != 0.2 || *requests[0].MaxTokens != 256 {
+		t.Fatalf("alias should use the route model and params: %+v", requests)
+	}
+	if *requests[1].Temperature != 0.9 {
+		t.Fatalf("explicit params should win, got %v", *requests[1].Temperature)
+	}
+	if requests := base.Requests(); len(requests) != 2 || requests[0].Model != "gpt-4o" || requests[1].Model != "gpt-4o-mini" || requests[0].Temperature != nil {
+		t.Fatalf("unexpected base requests: %+v", requests)
+	}
+
+	if _, err := u.Embeddings(context.Background(), *request.NewEmbeddingRequest(request.WithEmbeddingModel("embed"), request.WithInput("hi"))); err != nil {
+		t.Fatal(err)
+	}
+	if requests := other.EmbeddingRequests(); len(requests) != 1 || requests[0].Model != "text-embedding-v3" {
+		t.Fatalf("unexpected embedding requests: %+v", requests)
+	}
+
+	// 图像与语音请求同样按别名转发
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/images/generations":
+			var in request.ImageRequest
+			_ = sonic.ConfigDefault.NewDecoder(r.Body).Decode(&in)
+			w.Write([]byte(`{"created":1,"data":[{"url":"https://example.com/` + in.Model + `.png"}]}`))
+		case "/v1/audio/transcriptions":
+			w.Write([]byte(`{"text":"` + r.FormValue("model") + `"}`))
+		}
+	}))
+	defer srv.Close()
+	u = New(client.WithClient(base), client.WithRoutes(map[string]client.Route{
+		"draw":   {Options: []client.Option{client.WithType(client.OpenAI), client.WithHost(srv.URL)}, Model: "dall-e-3"},
+		"listen": {Options: []client.Option{client.WithType(client.OpenAI), client.WithHost(srv.URL)}, Model: "whisper-1"},
+	}))
+	img, err := u.Images().Generate(context.Background(), *request.NewImageRequest(request.WithImageModel("draw"), request.WithPrompt("cat")))
+	if err != nil || img.Data[0].URL != "https://example.com/dall-e-3.png" {
+		t.Fatalf("Generate = %+v, %v", img, err)
+	}
+	text, err := u.Audio().Transcribe(context.Background(), *request.NewTranscriptionRequest(request.WithTranscriptionModel("listen"), request.WithFile("a.mp3", strings.NewReader("RIFF"))))
+	if err != nil || text.Text != "whisper-1" {
+		t.Fatalf("Transcribe = %+v, %v", text, err)
+	}
+	if _, err = u.Images().Generate(context.Background(), *request.NewImageRequest(request.WithImageModel("dall-e-3"), request.WithPrompt("cat"))); !errors.Is(err, errorx.NotSupported) {
+		t.Fatalf("unrouted model should use the base client, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+type rejectAll struct{}
+
+func (rejectAll) Validate(request.Request) error { return errorx.InvalidInput }
+
+func Test_RouteInheritsOptions(t *testing.T) {
+	var got *http.Request
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		got = r
+		body := `{"id":"1","model":"glm-4","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+	})
+	route := client.Route{Options: []client.Option{client.WithType(client.Zhipu), client.AddHeader("Authorization", "id.secret")}, Model: "glm-4"}
+	u := New(client.WithTransport(rt), client.AddHeader("Authorization", "Bearer sk-openai"), client.WithRoute("glm", route))
+
+	out, err := u.Completions(context.Background(), *request.NewRequest(request.WithModel("glm"), request.WithStream(false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range out {
+	}
+	// 使用基础实例的传输层，发送到智谱的默认地址，且不携带基础实例的令牌
+	if got == nil || got.URL.Host != "open.bigmodel.cn" || strings.Contains(got.Header.Get("Authorization"), "sk-openai") {
+		t.Fatalf("unexpected routed request: %+v", got)
+	}
+
+	u = New(client.WithValidator(rejectAll{}), client.WithRoute("glm", route))
+	if _, err = u.Completions(context.Background(), *request.NewRequest(request.WithModel("glm"))); !errors.Is(err, errorx.InvalidInput) {
+		t.Fatalf("validator should apply to routed requests, got %v", err)
+	}
+}
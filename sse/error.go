@@ -0,0 +1,61 @@
+package sse
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+)
+
+// ErrorBody 是 OpenAI 格式的错误
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail 是 OpenAI 格式错误的详情
+type ErrorDetail struct {
+	Message string `json:"message"`        // 错误描述，服务商返回了错误描述时使用服务商的描述
+	Type    string `json:"type"`           // 错误类型，如 invalid_request_error、rate_limit_error
+	Code    string `json:"code,omitempty"` // 服务商返回的错误码
+}
+
+// upstreamErrorMessage 是无法归类的错误对外使用的描述，以免泄露上游地址等内部信息
+const upstreamErrorMessage = "upstream service error"
+
+// NewErrorBody 将错误转换为 OpenAI 格式的错误及对应的 HTTP 状态码，错误类别通过 errorx 中的错误判断。
+// errorx.InvalidRequest 只有来自服务商的响应（*errorx.APIError）时才视为请求错误，连接失败等同样包装为该类别的传输错误视为上游故障。
+// 无法归类的错误视为上游故障，返回 502 与通用的错误描述。
+func NewErrorBody(err error) (int, ErrorBody) {
+	body := ErrorBody{Error: ErrorDetail{Message: err.Error(), Type: "api_error"}}
+
+	var upstream *errorx.APIError
+	fromProvider := errors.As(err, &upstream)
+	if fromProvider {
+		body.Error.Code = upstream.Code
+		if upstream.Message != "" {
+			body.Error.Message = upstream.Message
+		}
+	}
+
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, errorx.InvalidInput), errors.Is(err, errorx.ContentFiltered), fromProvider && errors.Is(err, errorx.InvalidRequest):
+		status, body.Error.Type = http.StatusBadRequest, "invalid_request_error"
+	case errors.Is(err, errorx.Unauthorized):
+		status, body.Error.Type = http.StatusUnauthorized, "authentication_error"
+	case errors.Is(err, errorx.PermissionDenied):
+		status, body.Error.Type = http.StatusForbidden, "permission_error"
+	case errors.Is(err, errorx.NotFound):
+		status, body.Error.Type = http.StatusNotFound, "not_found_error"
+	case errors.Is(err, errorx.RateLimited), errors.Is(err, errorx.QuotaExceeded):
+		status, body.Error.Type = http.StatusTooManyRequests, "rate_limit_error"
+	case errors.Is(err, errorx.NotSupported):
+		status, body.Error.Type = http.StatusNotImplemented, "invalid_request_error"
+	case errors.Is(err, errorx.StreamTimeout):
+		status = http.StatusGatewayTimeout
+	case !fromProvider:
+		body.Error.Message = upstreamErrorMessage
+	}
+	return status, body
+}
@@ -0,0 +1,182 @@
+// Package sse 将补全结果以 SSE（Server-Sent Events）的形式转发给浏览器。
+// 它负责设置响应头、按规范组织 data 字段、及时刷新、定期发送保活注释、输出结束标记与错误事件，
+// 并在浏览器断开连接时取消上游的请求。
+package sse
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/response"
+)
+
+const (
+	FormatOpenAI = "openai" // 每个分片以 OpenAI 格式的 JSON 输出，流以 data: [DONE] 结束
+	FormatText   = "text"   // 每个分片只输出增量文本，流以 done 事件结束
+)
+
+// Options 结构体定义了输出 SSE 的选项
+type Options struct {
+	Format    string        // 分片的格式，默认为 FormatOpenAI
+	KeepAlive time.Duration // 发送保活注释的间隔，0 表示不发送，默认为 15 秒
+	Model     string        // 覆盖分片中的模型名称，为空时保留原值
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// NewOptions 创建一个新的 Options 实例，默认使用 OpenAI 格式并每 15 秒发送一次保活注释。
+func NewOptions(opts ...Option) *Options {
+	o := &Options{Format: FormatOpenAI, KeepAlive: 15 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithFormat 设置分片的格式，可选 FormatOpenAI、FormatText
+func WithFormat(format string) Option {
+	return func(o *Options) { o.Format = format }
+}
+
+// WithKeepAlive 设置发送保活注释的间隔，避免代理因连接空闲而断开，0 表示不发送
+func WithKeepAlive(d time.Duration) Option {
+	return func(o *Options) { o.KeepAlive = d }
+}
+
+// WithModel 设置输出分片中的模型名称，如网关中带有服务商前缀的名称
+func WithModel(model string) Option {
+	return func(o *Options) { o.Model = model }
+}
+
+// Write 将 s 中的结果以 SSE 的形式写入 w，直到流结束、出错或浏览器断开连接，返回前总会关闭 s 以释放上游的连接。
+// 流正常结束时输出结束标记并返回 nil；流因错误结束时输出 error 事件并返回该错误；
+// 浏览器断开连接时返回 r.Context().Err()。持有通道的调用方可以使用 response.NewStream 创建 s。
+func Write(w http.ResponseWriter, r *http.Request, s *response.Stream, opts ...Option) error {
+	defer s.Close()
+	o := NewOptions(opts...)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// 关闭 nginx 等反向代理的缓冲
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	out := &writer{w: w, rc: http.NewResponseController(w)}
+	if err := out.flush(); err != nil {
+		return err
+	}
+
+	var tick <-chan time.Time
+	if o.KeepAlive > 0 {
+		ticker := time.NewTicker(o.KeepAlive)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case <-tick:
+			if err := out.comment("keep-alive"); err != nil {
+				return err
+			}
+		case resp, ok := <-s.C:
+			if !ok {
+				if o.Format == FormatText {
+					return out.event("done", "")
+				}
+				return out.event("", "[DONE]")
+			}
+			if resp.Err != nil {
+				_, body := NewErrorBody(resp.Err)
+				data, _ := sonic.ConfigDefault.MarshalToString(body)
+				if err := out.event("error", data); err != nil {
+					return err
+				}
+				return resp.Err
+			}
+			if err := out.response(resp, o); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writer 输出 SSE 事件并在每个事件后刷新
+type writer struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+// response 按格式输出一个分片，文本格式下没有增量文本的分片会被跳过
+func (w *writer) response(resp response.Response, o *Options) error {
+	if o.Format == FormatText {
+		if text := content(resp); text != "" {
+			return w.event("", text)
+		}
+		return nil
+	}
+
+	if o.Model != "" {
+		resp.Model = o.Model
+	}
+	if resp.Object == "" {
+		resp.Object = "chat.completion.chunk"
+	}
+	data, err := sonic.ConfigDefault.MarshalToString(resp)
+	if err != nil {
+		return errors.Wrap(err, "sse: marshal chunk")
+	}
+	return w.event("", data)
+}
+
+// event 输出一个事件，多行数据会被拆分为多个 data 字段
+func (w *writer) event(name, data string) error {
+	var b strings.Builder
+	if name != "" {
+		b.WriteString("event: " + name + "\n")
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return w.write(b.String())
+}
+
+// comment 输出一行注释，浏览器会忽略注释，常用于保持连接
+func (w *writer) comment(text string) error {
+	return w.write(": " + text + "\n\n")
+}
+
+func (w *writer) write(s string) error {
+	if _, err := io.WriteString(w.w, s); err != nil {
+		return errors.Wrap(err, "sse: write")
+	}
+	return w.flush()
+}
+
+func (w *writer) flush() error {
+	return errors.Wrap(w.rc.Flush(), "sse: flush")
+}
+
+// content 返回分片中第一个选项的文本，兼容流式分片与完整响应
+func content(resp response.Response) string {
+	if len(resp.Choices) == 0 {
+		return ""
+	}
+	if c := resp.Choices[0]; c.Delta != nil {
+		return c.Delta.Content
+	} else if c.Message != nil {
+		return c.Message.Content
+	}
+	return ""
+}
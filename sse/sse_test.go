@@ -0,0 +1,104 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/response"
+)
+
+func chunk(content string) response.Response {
+	return response.Response{Choices: []response.Choices{{Delta: &response.Delta{Content: content}}}}
+}
+
+// stream 返回依次输出 items 的流，取消后通道会被关闭
+func stream(delay time.Duration, items ...response.Response) (*response.Stream, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan response.Response)
+	go func() {
+		defer close(out)
+		for _, item := range items {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return response.NewStream(out, cancel), ctx
+}
+
+func write(r *http.Request, s *response.Stream, opts ...Option) (string, error) {
+	w := httptest.NewRecorder()
+	err := Write(w, r, s, opts...)
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		return "", errors.Errorf("Content-Type = %q", ct)
+	}
+	return w.Body.String(), err
+}
+
+func Test_Write(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	s, _ := stream(0, chunk("你"), chunk("好"))
+	got, err := write(r, s, WithModel("tongyi/qwen-max"))
+	if err != nil || !strings.Contains(got, `"content":"好"`) || !strings.Contains(got, `"model":"tongyi/qwen-max"`) || !strings.HasSuffix(got, "data: [DONE]\n\n") {
+		t.Fatalf("openai format: %q, %v", got, err)
+	}
+
+	s, _ = stream(0, chunk("第一行\n第二行"), response.Response{Err: errorx.RateLimited})
+	got, err = write(r, s, WithFormat(FormatText))
+	want := "data: 第一行\ndata: 第二行\n\nevent: error\ndata: "
+	if !errors.Is(err, errorx.RateLimited) || !strings.HasPrefix(got, want) || !strings.Contains(got, "rate_limit_error") {
+		t.Fatalf("text format: %q, %v", got, err)
+	}
+
+	s, _ = stream(30*time.Millisecond, chunk("a"))
+	got, err = write(r, s, WithFormat(FormatText), WithKeepAlive(5*time.Millisecond))
+	if err != nil || !strings.HasPrefix(got, ": keep-alive\n\n") || !strings.HasSuffix(got, "data: a\n\nevent: done\ndata: \n\n") {
+		t.Fatalf("keep-alive: %q, %v", got, err)
+	}
+
+	// 浏览器断开连接后应取消上游的请求
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s, upstream := stream(time.Hour, chunk("a"))
+	if _, err = write(r.WithContext(ctx), s); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if upstream.Err() == nil {
+		t.Fatal("upstream request should be canceled")
+	}
+}
+
+func Test_NewErrorBody(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"input", errors.Wrap(errorx.InvalidInput, "bad json"), http.StatusBadRequest, "bad json: " + errorx.InvalidInput.Error()},
+		{"provider 400", errorx.NewAPIError("openai", http.StatusBadRequest, []byte(`{"error":{"message":"bad model"}}`)), http.StatusBadRequest, "bad model"},
+		{"transport", errors.Wrap(errorx.InvalidRequest, `Post "https://internal:8443/v1": dial tcp: connection refused`), http.StatusBadGateway, upstreamErrorMessage},
+		{"provider 500", errorx.NewAPIError("openai", http.StatusInternalServerError, []byte(`{"error":{"message":"overloaded"}}`)), http.StatusBadGateway, "overloaded"},
+		{"rate limited", errorx.RateLimited, http.StatusTooManyRequests, errorx.RateLimited.Error()},
+	} {
+		status, body := NewErrorBody(tt.err)
+		if status != tt.status || body.Error.Message != tt.message {
+			t.Errorf("%s: %d %+v", tt.name, status, body.Error)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/errorx"
+)
+
+// 以下为文件存储支持的格式
+const (
+	FormatJSONL = "jsonl" // 每行一条消息，追加消息时只需在文件末尾写入，适合长会话
+	FormatJSON  = "json"  // 整个会话保存为一个 JSON 对象，便于人工查看，追加消息时会重写整个文件
+)
+
+// File 是基于本地文件的会话存储，每个会话保存为目录下的一个文件，文件名为转义后的会话标识。
+// 同一进程内的读写通过互斥锁保证并发安全；JSONL 格式以追加方式写入，多个进程同时追加同一会话时每条消息不会交错。
+type File struct {
+	mu     sync.Mutex
+	dir    string
+	format string
+	opts   Options
+}
+
+// NewFile 创建一个文件会话存储，format 为 FormatJSONL 或 FormatJSON，目录不存在时会自动创建
+func NewFile(dir, format string, opts ...Option) (*File, error) {
+	if format != FormatJSONL && format != FormatJSON {
+		return nil, errors.Wrapf(errorx.InvalidInput, "unknown store format %q", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir, format: format, opts: newOptions(opts...)}, nil
+}
+
+// Load 读取会话
+func (f *File) Load(_ context.Context, id string) (*uniai.ConversationRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.load(id)
+}
+
+// Append 向会话追加消息，会话不存在或已过期时创建新的会话
+func (f *File) Append(_ context.Context, id string, turns ...uniai.Turn) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.format == FormatJSONL {
+		return f.appendLines(id, turns)
+	}
+
+	record, err := f.load(id)
+	if errors.Is(err, errorx.NotFound) {
+		record, err = &uniai.ConversationRecord{ID: id, CreatedAt: time.Now()}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	record.Turns = append(record.Turns, turns...)
+	record.UpdatedAt = time.Now()
+	data, err := sonic.ConfigDefault.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// 先写入临时文件再重命名，避免写入过程中进程退出导致文件损坏。
+	tmp := f.path(id) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(id))
+}
+
+// List 列出所有未过期的会话
+func (f *File) List(_ context.Context) ([]uniai.ConversationInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]uniai.ConversationInfo, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), "."+f.format)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		id, err := url.PathUnescape(name)
+		if err != nil {
+			continue
+		}
+
+		record, err := f.load(id)
+		if errors.Is(err, errorx.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, uniai.ConversationInfo{ID: id, Turns: len(record.Turns), UpdatedAt: record.UpdatedAt})
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Delete 删除会话
+func (f *File) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(f.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 返回会话对应的文件路径
+func (f *File) path(id string) string {
+	return filepath.Join(f.dir, url.PathEscape(id)+"."+f.format)
+}
+
+// load 读取会话文件，过期的会话会被删除，调用方需持有锁
+func (f *File) load(id string) (*uniai.ConversationRecord, error) {
+	path := f.path(id)
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, errorx.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.opts.expired(stat.ModTime()) {
+		os.Remove(path)
+		return nil, errorx.NotFound
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.format == FormatJSON {
+		var record uniai.ConversationRecord
+		if err := sonic.ConfigDefault.Unmarshal(data, &record); err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		return &record, nil
+	}
+
+	record := &uniai.ConversationRecord{ID: id, UpdatedAt: stat.ModTime()}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var turn uniai.Turn
+		if err := sonic.ConfigDefault.Unmarshal(line, &turn); err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		record.Turns = append(record.Turns, turn)
+	}
+	if len(record.Turns) > 0 {
+		record.CreatedAt = record.Turns[0].CreatedAt
+	}
+	return record, scanner.Err()
+}
+
+// appendLines 以 JSONL 格式追加消息，所有消息在一次写入中完成，调用方需持有锁
+func (f *File) appendLines(id string, turns []uniai.Turn) error {
+	path := f.path(id)
+	if stat, err := os.Stat(path); err == nil && f.opts.expired(stat.ModTime()) {
+		os.Remove(path)
+	}
+
+	var buf bytes.Buffer
+	for _, turn := range turns {
+		line, err := sonic.ConfigDefault.Marshal(turn)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
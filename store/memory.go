@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/errorx"
+)
+
+// Memory 是基于内存的会话存储，进程退出后数据会丢失，适用于测试与单机场景
+type Memory struct {
+	mu      sync.Mutex
+	opts    Options
+	records map[string]*uniai.ConversationRecord
+}
+
+// NewMemory 创建一个内存会话存储
+func NewMemory(opts ...Option) *Memory {
+	return &Memory{opts: newOptions(opts...), records: make(map[string]*uniai.ConversationRecord)}
+}
+
+// Load 读取会话，返回的记录是副本，修改它不会影响存储中的数据
+func (m *Memory) Load(_ context.Context, id string) (*uniai.ConversationRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[id]
+	if !ok {
+		return nil, errorx.NotFound
+	}
+	if m.opts.expired(record.UpdatedAt) {
+		delete(m.records, id)
+		return nil, errorx.NotFound
+	}
+
+	out := *record
+	out.Turns = append([]uniai.Turn(nil), record.Turns...)
+	return &out, nil
+}
+
+// Append 向会话追加消息，会话不存在或已过期时创建新的会话
+func (m *Memory) Append(_ context.Context, id string, turns ...uniai.Turn) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	record, ok := m.records[id]
+	if !ok || m.opts.expired(record.UpdatedAt) {
+		record = &uniai.ConversationRecord{ID: id, CreatedAt: now}
+		m.records[id] = record
+	}
+	record.Turns = append(record.Turns, turns...)
+	record.UpdatedAt = now
+	return nil
+}
+
+// List 列出所有未过期的会话
+func (m *Memory) List(_ context.Context) ([]uniai.ConversationInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]uniai.ConversationInfo, 0, len(m.records))
+	for id, record := range m.records {
+		if m.opts.expired(record.UpdatedAt) {
+			delete(m.records, id)
+			continue
+		}
+		infos = append(infos, uniai.ConversationInfo{ID: id, Turns: len(record.Turns), UpdatedAt: record.UpdatedAt})
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Delete 删除会话
+func (m *Memory) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
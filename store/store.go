@@ -0,0 +1,41 @@
+// Package store 提供 uniai.ConversationStore 的内置实现，包括内存存储与基于本地文件的 JSON/JSONL 存储。
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jun3372/uniai"
+)
+
+// Options 结构体定义了存储的选项
+type Options struct {
+	TTL time.Duration // 会话的有效期，从最后一次追加消息开始计算，0 表示永不过期
+}
+
+// Option 是一个函数类型，用于修改 Options 结构体
+type Option func(*Options)
+
+// WithTTL 设置会话的有效期，过期的会话在读取或列出时被视为不存在并被清理
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) { o.TTL = ttl }
+}
+
+// newOptions 创建一个新的 Options 实例
+func newOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// expired 判断最后更新时间为 updatedAt 的会话是否已过期
+func (o Options) expired(updatedAt time.Time) bool {
+	return o.TTL > 0 && time.Since(updatedAt) > o.TTL
+}
+
+// sortInfos 按最后更新时间倒序排列会话
+func sortInfos(infos []uniai.ConversationInfo) {
+	sort.Slice(infos, func(i, j int) bool { return infos[i].UpdatedAt.After(infos[j].UpdatedAt) })
+}
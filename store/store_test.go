@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+func Test_Stores(t *testing.T) {
+	jsonl, err := NewFile(t.TempDir(), FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json, err := NewFile(t.TempDir(), FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, s := range map[string]uniai.ConversationStore{"memory": NewMemory(), "jsonl": jsonl, "json": json} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if _, err := s.Load(ctx, "a/b"); !errors.Is(err, errorx.NotFound) {
+				t.Fatalf("Load missing = %v", err)
+			}
+
+			reply := request.NewAssistantMessage("")
+			reply.ToolCalls = []request.ToolCall{{ID: "call_1", Type: "function", Function: request.FunctionCall{Name: "f", Arguments: "{}"}}}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := s.Append(ctx, "a/b",
+						uniai.Turn{Message: request.NewUserMessage("hi"), CreatedAt: time.Now()},
+						uniai.Turn{Message: reply, Provider: "openai", Model: "gpt-4o", Usage: &response.Usage{TotalTokens: 3}, CreatedAt: time.Now()},
+					)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			record, err := s.Load(ctx, "a/b")
+			if err != nil || len(record.Turns) != 20 {
+				t.Fatalf("Load = %+v, %v", record, err)
+			}
+			if turn := record.Turns[1]; turn.Model != "gpt-4o" || turn.Usage.TotalTokens != 3 || turn.Message.ToolCalls[0].ID != "call_1" {
+				t.Fatalf("unexpected turn: %+v", turn)
+			}
+
+			infos, err := s.List(ctx)
+			if err != nil || len(infos) != 1 || infos[0].ID != "a/b" || infos[0].Turns != 20 {
+				t.Fatalf("List = %+v, %v", infos, err)
+			}
+
+			if err := s.Delete(ctx, "a/b"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Load(ctx, "a/b"); !errors.Is(err, errorx.NotFound) {
+				t.Fatalf("Load deleted = %v", err)
+			}
+		})
+	}
+}
+
+func Test_TTL(t *testing.T) {
+	s := NewMemory(WithTTL(time.Millisecond))
+	s.Append(context.Background(), "a", uniai.Turn{Message: request.NewUserMessage("hi")})
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := s.Load(context.Background(), "a"); !errors.Is(err, errorx.NotFound) {
+		t.Fatalf("Load expired = %v", err)
+	}
+}
+
+// reply 固定回复 ok 的客户端
+type reply struct{}
+
+// Completions 在用户询问天气时返回工具调用，其余情况回复 ok
+func (reply) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	msg := &response.Message{Role: "assistant", Content: "ok"}
+	if last := in.Messages[len(in.Messages)-1]; last.Content == "weather?" {
+		msg = &response.Message{Role: "assistant", ToolCalls: []response.ToolCall{{ID: "call_1", Type: "function", Function: response.FunctionCall{Name: "weather", Arguments: "{}"}}}}
+	}
+
+	out := make(chan response.Response, 1)
+	out <- response.Response{Model: "m", Choices: []response.Choices{{Message: msg}}}
+	close(out)
+	return out, nil
+}
+
+func Test_Resume(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+
+	conv, _ := uniai.LoadConversation(ctx, reply{}, s, "c1")
+	if _, err := conv.Ask(ctx, "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := uniai.LoadConversation(ctx, reply{}, s, "c1")
+	if err != nil || len(resumed.Messages()) != 2 || resumed.Messages()[1].Content != "ok" {
+		t.Fatalf("resumed = %+v, %v", resumed.Messages(), err)
+	}
+
+	// 工具调用的结果通过 Append 追加，恢复后完整保留
+	msg, err := resumed.Ask(ctx, "weather?")
+	if err != nil || len(msg.ToolCalls) != 1 {
+		t.Fatalf("Ask = %+v, %v", msg, err)
+	}
+	if err := resumed.Append(ctx, request.NewToolMessage(msg.ToolCalls[0].ID, "sunny")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resumed.Ask(ctx, "thanks"); err != nil {
+		t.Fatal(err)
+	}
+
+	again, _ := uniai.LoadConversation(ctx, reply{}, s, "c1")
+	history := again.Messages()
+	if len(history) != 7 || history[3].ToolCalls[0].ID != "call_1" || history[4].Role != request.MessageRoleTool || history[4].ToolCallID != "call_1" || history[6].Content != "ok" {
+		t.Fatalf("resumed history = %+v", history)
+	}
+}
@@ -0,0 +1,202 @@
+package uniai
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/jsonschema"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// Completer 接口定义了获取补全结果的方法，New 返回的实例实现了该接口。
+type Completer interface {
+	Completions(ctx context.Context, in request.Request) (chan response.Response, error)
+}
+
+// Validator 接口由需要额外业务校验的结构化输出类型实现。
+type Validator interface {
+	Validate() error
+}
+
+// StructuredOptions 结构体定义了结构化输出的选项
+type StructuredOptions struct {
+	MaxRetries int    // 校验失败后的最大重试次数
+	Name       string // JSON Schema 的名称，默认为类型名称
+	Strict     bool   // 是否要求服务商严格遵循 Schema
+}
+
+// StructuredOption 是一个函数类型，用于修改 StructuredOptions 结构体
+type StructuredOption func(*StructuredOptions)
+
+// WithMaxRetries 设置校验失败后的最大重试次数
+func WithMaxRetries(n int) StructuredOption {
+	return func(o *StructuredOptions) { o.MaxRetries = n }
+}
+
+// WithSchemaName 设置 JSON Schema 的名称
+func WithSchemaName(name string) StructuredOption {
+	return func(o *StructuredOptions) { o.Name = name }
+}
+
+// WithStrict 设置是否要求服务商严格遵循 Schema。
+// 严格模式下结构体的所有字段都必须是必填字段，即不能声明 omitempty。
+func WithStrict(strict bool) StructuredOption {
+	return func(o *StructuredOptions) { o.Strict = strict }
+}
+
+// Structured 调用模型并将回答解码为类型 T。
+// 它会根据 T 生成 JSON Schema 并以 json_schema 模式发起请求，不支持该模式的服务商会以提示词的方式模拟。
+// 回答无法解析、不符合 Schema 或未通过 Validator 校验时，会将错误信息反馈给模型并重试，
+// 重试次数用尽后返回 errorx.InvalidOutput。
+func Structured[T any](ctx context.Context, c Completer, in request.Request, opts ...StructuredOption) (T, error) {
+	var zero T
+	o := &StructuredOptions{MaxRetries: 2, Name: schemaName(reflect.TypeOf((*T)(nil)).Elem())}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.MaxRetries = max(o.MaxRetries, 0)
+
+	schema := jsonschema.For[T]()
+	in.ResponseFormat = &request.ResponseFormat{
+		Type:       request.ResponseFormatJSONSchema,
+		JSONSchema: &request.JSONSchema{Name: o.Name, Schema: schema, Strict: o.Strict},
+	}
+	in.Messages = append([]request.Messages(nil), in.Messages...)
+
+	var lastErr error
+	for attempt := 0; attempt <= o.MaxRetries; attempt++ {
+		out, err := c.Completions(ctx, in)
+		if err != nil {
+			return zero, err
+		}
+
+		content, err := collect(ctx, out)
+		if err != nil {
+			return zero, err
+		}
+
+		value, err := decodeStructured[T](content, schema)
+		if err == nil {
+			return value, nil
+		}
+
+		lastErr = err
+		in.Messages = append(in.Messages,
+			request.NewAssistantMessage(content),
+			request.NewUserMessage("上面的输出未通过校验，错误如下：\n"+err.Error()+"\n请修正后重新输出完整的 JSON 对象，不要输出任何其他内容。"),
+		)
+	}
+
+	return zero, &outputError{err: lastErr}
+}
+
+// outputError 表示重试次数用尽后仍未通过校验的回答。
+// 它可以通过 errors.Is(err, errorx.InvalidOutput) 判断，同时保留最后一次校验的错误。
+type outputError struct {
+	err error
+}
+
+// Error 实现 error 接口，返回可读的错误描述。
+func (e *outputError) Error() string {
+	return e.err.Error() + ": " + errorx.InvalidOutput.Error()
+}
+
+// Unwrap 返回 InvalidOutput 与最后一次校验的错误，使 errors.Is 对两者均生效。
+func (e *outputError) Unwrap() []error {
+	return []error{errorx.InvalidOutput, e.err}
+}
+
+// collect 读取补全结果，返回第一个选项的完整内容
+func collect(ctx context.Context, out chan response.Response) (string, error) {
+	acc := response.NewAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case item, ok := <-out:
+			if !ok {
+				return acc.Message().Content, acc.Err()
+			}
+			acc.Add(item)
+		}
+	}
+}
+
+// decodeStructured 从回答中提取 JSON，校验后解码为类型 T
+func decodeStructured[T any](content string, schema *jsonschema.Schema) (T, error) {
+	var value T
+	data := extractJSON(content)
+
+	var raw any
+	if err := sonic.ConfigDefault.UnmarshalFromString(data, &raw); err != nil {
+		return value, errors.Wrap(err, "output is not valid json")
+	}
+	if err := jsonschema.Validate(schema, raw); err != nil {
+		return value, err
+	}
+	if err := sonic.ConfigDefault.UnmarshalFromString(data, &value); err != nil {
+		return value, err
+	}
+
+	if v, ok := any(&value).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return value, err
+		}
+	}
+	return value, nil
+}
+
+// extractJSON 去掉回答中的 Markdown 代码块与前后多余的文字，返回 JSON 部分
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+		content = strings.TrimSpace(content)
+	}
+
+	// 从每个可能的起点解码第一个完整的 JSON 值，忽略其后的文字，如 {"a":1} (see [1])
+	for offset := 0; offset < len(content); {
+		i := strings.IndexAny(content[offset:], "{[")
+		if i < 0 {
+			break
+		}
+		var raw json.RawMessage
+		if err := sonic.ConfigDefault.NewDecoder(strings.NewReader(content[offset+i:])).Decode(&raw); err == nil {
+			return string(raw)
+		}
+		offset += i + 1
+	}
+
+	// 没有完整的 JSON 值时返回最外层的括号之间的内容，解析错误会反馈给模型
+	start, end := strings.IndexAny(content, "{["), strings.LastIndexAny(content, "}]")
+	if start >= 0 && end > start {
+		return content[start : end+1]
+	}
+	return content
+}
+
+// schemaName 根据类型生成 Schema 名称，服务商要求名称仅包含字母、数字、下划线与中划线
+func schemaName(t reflect.Type) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, t.Name())
+
+	if name == "" {
+		return "response"
+	}
+	return name
+}
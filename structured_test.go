@@ -0,0 +1,92 @@
+package uniai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// replies 按顺序返回预设回答的 Completer
+type replies struct {
+	contents []string
+	requests []request.Request
+}
+
+func (r *replies) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	r.requests = append(r.requests, in)
+	out := make(chan response.Response, 1)
+	out <- response.Response{Choices: []response.Choices{{Message: &response.Message{Role: "assistant", Content: r.contents[0]}}}}
+	r.contents = r.contents[1:]
+	close(out)
+	return out, nil
+}
+
+type poem struct {
+	Title string `json:"title"`
+	Lines int    `json:"lines"`
+}
+
+func (p poem) Validate() error {
+	if p.Lines <= 0 {
+		return errors.New("lines must be positive")
+	}
+	return nil
+}
+
+func Test_Structured(t *testing.T) {
+	c := &replies{contents: []string{
+		`{"title":"春晓"}`,
+		`{"title":"春晓","lines":0}`,
+		"```json\n{\"title\":\"春晓\",\"lines\":4}\n```",
+	}}
+
+	in := *request.NewRequest(request.WithMessages([]request.Messages{request.NewUserMessage("写一首诗")}))
+	got, err := Structured[poem](context.Background(), c, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "春晓" || got.Lines != 4 {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+
+	if len(c.requests) != 3 || len(c.requests[2].Messages) != 5 {
+		t.Fatalf("validation errors should be fed back, got %d requests", len(c.requests))
+	}
+	if c.requests[0].ResponseFormat.Type != request.ResponseFormatJSONSchema || c.requests[0].ResponseFormat.JSONSchema.Name != "poem" {
+		t.Fatal("request should use json_schema response format")
+	}
+}
+
+func Test_ExtractJSON(t *testing.T) {
+	for in, want := range map[string]string{
+		`{"a":1} (see [1])`:               `{"a":1}`,
+		"结果 [注1]：\n```json\n[1,2]\n```":   `[1,2]`,
+		"```json\n{\"a\":{\"b\":2}}\n```": `{"a":{"b":2}}`,
+		`{"a":`:                           `{"a":`,
+		"不是 JSON":                         "不是 JSON",
+	} {
+		if got := extractJSON(in); got != want {
+			t.Errorf("extractJSON(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func Test_StructuredExhausted(t *testing.T) {
+	c := &replies{contents: []string{"不是 JSON", "仍然不是"}}
+	_, err := Structured[poem](context.Background(), c, *request.NewRequest(), WithMaxRetries(1))
+	if !errors.Is(err, errorx.InvalidOutput) {
+		t.Fatalf("expected invalid output error, got %v", err)
+	}
+
+	// 负数的重试次数按 0 处理，最后一次校验的错误保留在错误链中
+	c = &replies{contents: []string{`{"title":"静夜思","lines":0} 以上是输出`}}
+	_, err = Structured[poem](context.Background(), c, *request.NewRequest(), WithMaxRetries(-1))
+	if !errors.Is(err, errorx.InvalidOutput) || err.Error() != "lines must be positive: "+errorx.InvalidOutput.Error() || len(c.requests) != 1 {
+		t.Fatalf("unexpected error %v after %d requests", err, len(c.requests))
+	}
+}
@@ -0,0 +1,195 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// 以下为内置支持的编码名称，与 tiktoken 的词表文件名一致
+const (
+	Cl100kBase = "cl100k_base" // gpt-4、gpt-3.5-turbo、text-embedding-3 使用的编码
+	O200kBase  = "o200k_base"  // gpt-4o、gpt-4.1、o1、o3 使用的编码
+	Qwen       = "qwen"        // 通义千问使用的编码
+)
+
+// patterns 是各编码的预分词正则。
+// tiktoken 的原始正则以 \s+(?!\S)|\s+ 结尾，Go 的正则不支持零宽断言，
+// 这里统一以 \s+ 代替，由 split 在匹配后回退最后一个空白字符实现相同的效果。
+var patterns = map[string]string{
+	Cl100kBase: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`,
+	O200kBase: `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`,
+	Qwen: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`,
+}
+
+// BPE 是基于字节对合并（Byte Pair Encoding）的分词器，兼容 tiktoken 格式的词表。
+type BPE struct {
+	ranks   map[string]int // 字节序列到 Token ID 的映射，ID 越小合并优先级越高
+	decoder map[int]string // Token ID 到字节序列的映射
+	pattern *regexp.Regexp // 预分词正则
+}
+
+// NewBPE 根据词表与编码名称创建一个 BPE 分词器，编码名称决定了使用的预分词正则。
+func NewBPE(ranks map[string]int, encoding string) (*BPE, error) {
+	pattern, ok := patterns[encoding]
+	if !ok {
+		return nil, errors.Errorf("tokenizer: unknown encoding %q", encoding)
+	}
+
+	decoder := make(map[int]string, len(ranks))
+	for k, v := range ranks {
+		decoder[v] = k
+	}
+	return &BPE{ranks: ranks, decoder: decoder, pattern: regexp.MustCompile(`^(?:` + pattern + `)`)}, nil
+}
+
+// LoadFile 从本地的 tiktoken 格式词表文件创建 BPE 分词器，如 cl100k_base.tiktoken、qwen.tiktoken。
+func LoadFile(path, encoding string) (*BPE, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ranks, err := ReadTiktoken(f)
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	return NewBPE(ranks, encoding)
+}
+
+// ReadTiktoken 读取 tiktoken 格式的词表，每行为 base64 编码的字节序列与对应的 Token ID，以空格分隔。
+func ReadTiktoken(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		token, rank, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, errors.Errorf("tokenizer: invalid vocab line %d", line)
+		}
+		raw, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "tokenizer: invalid vocab line %d", line)
+		}
+		id, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, errors.Wrapf(err, "tokenizer: invalid vocab line %d", line)
+		}
+		ranks[string(raw)] = id
+	}
+	return ranks, scanner.Err()
+}
+
+// Encode 将文本编码为 Token ID 列表，特殊 Token（如 <|endoftext|>）按普通文本处理。
+// 词表中缺失的单个字节会被编码为 -1。
+func (b *BPE) Encode(text string) []int {
+	ids := make([]int, 0, len(text)/3)
+	for _, piece := range b.split(text) {
+		if id, ok := b.ranks[piece]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		ids = append(ids, b.merge(piece)...)
+	}
+	return ids
+}
+
+// Count 返回文本编码后的 Token 数量
+func (b *BPE) Count(text string) int {
+	return len(b.Encode(text))
+}
+
+// Decode 将 Token ID 列表还原为文本，未知的 ID 会被忽略。
+func (b *BPE) Decode(ids []int) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		sb.WriteString(b.decoder[id])
+	}
+	return sb.String()
+}
+
+// split 按预分词正则将文本切分为片段
+func (b *BPE) split(text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		loc := b.pattern.FindStringIndex(text)
+		if loc == nil || loc[1] == 0 {
+			// 正则覆盖了所有字符，这里仅作为兜底，避免死循环。
+			_, size := utf8.DecodeRuneInString(text)
+			pieces = append(pieces, text[:size])
+			text = text[size:]
+			continue
+		}
+
+		end := loc[1]
+		// 模拟 \s+(?!\S)：多个空白后紧跟非空白字符时，最后一个空白字符归入下一个片段。
+		if end < len(text) && isSpaces(text[:end]) && !strings.ContainsAny(text[end-1:end], "\r\n") {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if last, size := utf8.DecodeLastRuneInString(text[:end]); !unicode.IsSpace(next) && size < end && unicode.IsSpace(last) {
+				end -= size
+			}
+		}
+
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
+
+// merge 对单个片段执行字节对合并，每次合并优先级最高（ID 最小）的相邻字节对
+func (b *BPE) merge(piece string) []int {
+	parts := make([]string, 0, len(piece))
+	for i := 0; i < len(piece); i++ {
+		parts = append(parts, piece[i:i+1])
+	}
+
+	for len(parts) > 1 {
+		best, at := math.MaxInt, -1
+		for i := 0; i < len(parts)-1; i++ {
+			if rank, ok := b.ranks[parts[i]+parts[i+1]]; ok && rank < best {
+				best, at = rank, i
+			}
+		}
+		if at < 0 {
+			break
+		}
+		parts[at] += parts[at+1]
+		parts = append(parts[:at+1], parts[at+2:]...)
+	}
+
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		if id, ok := b.ranks[part]; ok {
+			ids = append(ids, id)
+		} else {
+			ids = append(ids, -1)
+		}
+	}
+	return ids
+}
+
+// isSpaces 判断字符串是否全部由空白字符组成
+func isSpaces(s string) bool {
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
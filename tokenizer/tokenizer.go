@@ -0,0 +1,134 @@
+// Package tokenizer 提供离线的 Token 计数能力，用于在发送请求前估算提示词长度、裁剪历史消息与控制预算。
+//
+// BPE 分词器从本地的 tiktoken 格式词表加载（cl100k_base、o200k_base、qwen），不会访问网络；
+// 未加载词表的模型使用 Estimator 按字符类型估算。
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/jun3372/uniai/request"
+)
+
+// Tokenizer 接口定义了分词器需要实现的方法
+type Tokenizer interface {
+	// Count 返回文本的 Token 数量
+	Count(text string) int
+}
+
+// Estimator 是一个无需词表的估算分词器，用于未加载词表的模型。
+// 中日韩字符按每个字符 1 个 Token 计算，其余字符按每 4 个字节 1 个 Token 计算。
+type Estimator struct{}
+
+// Count 返回文本的估算 Token 数量
+func (Estimator) Count(text string) int {
+	var tokens, bytes int
+	for _, r := range text {
+		if isCJK(r) {
+			tokens++
+			tokens += (bytes + 3) / 4
+			bytes = 0
+			continue
+		}
+		bytes += utf8.RuneLen(r)
+	}
+	return tokens + (bytes+3)/4
+}
+
+// isCJK 判断字符是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+var (
+	mu        sync.RWMutex
+	encodings = make(map[string]Tokenizer)
+)
+
+// Register 注册指定编码的分词器，同名编码会被覆盖
+func Register(encoding string, t Tokenizer) {
+	mu.Lock()
+	defer mu.Unlock()
+	encodings[encoding] = t
+}
+
+// LoadDir 从目录中加载 tiktoken 格式的词表并注册，文件名需与编码名称一致，如 cl100k_base.tiktoken。
+// 目录中不存在的词表会被跳过，返回成功加载的编码名称。
+func LoadDir(dir string) ([]string, error) {
+	var loaded []string
+	for encoding := range patterns {
+		path := filepath.Join(dir, encoding+".tiktoken")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		bpe, err := LoadFile(path, encoding)
+		if err != nil {
+			return loaded, err
+		}
+		Register(encoding, bpe)
+		loaded = append(loaded, encoding)
+	}
+	return loaded, nil
+}
+
+// EncodingForModel 返回模型使用的编码名称，无法识别的模型返回空字符串
+func EncodingForModel(model string) string {
+	model = strings.ToLower(model)
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "gpt-4.1"), strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"), strings.HasPrefix(model, "o4"):
+		return O200kBase
+	case strings.HasPrefix(model, "gpt-4"), strings.HasPrefix(model, "gpt-3.5"), strings.HasPrefix(model, "text-embedding-3"), strings.HasPrefix(model, "text-embedding-ada"):
+		return Cl100kBase
+	case strings.HasPrefix(model, "qwen"), strings.HasPrefix(model, "qwq"):
+		return Qwen
+	default:
+		return ""
+	}
+}
+
+// ForModel 返回模型对应的分词器，未加载对应词表时返回 Estimator
+func ForModel(model string) Tokenizer {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, ok := encodings[EncodingForModel(model)]; ok {
+		return t
+	}
+	return Estimator{}
+}
+
+// 以下为对话格式的额外开销，取自 OpenAI 公布的计算方式
+const (
+	tokensPerMessage = 3 // 每条消息的角色与分隔符
+	tokensPerReply   = 3 // 回复的起始标记
+)
+
+// CountMessages 使用指定的分词器计算消息列表的 Token 数量，包含每条消息的角色开销与回复的起始标记
+func CountMessages(t Tokenizer, messages []request.Messages) int {
+	total := tokensPerReply
+	for _, msg := range messages {
+		total += tokensPerMessage + t.Count(msg.Role) + t.Count(msg.Content)
+		for _, call := range msg.ToolCalls {
+			total += t.Count(call.Function.Name) + t.Count(call.Function.Arguments)
+		}
+	}
+	return total
+}
+
+// CountRequest 计算请求中提示词的 Token 数量，包含消息与工具定义，分词器由请求的模型决定
+func CountRequest(in request.Request) int {
+	t := ForModel(in.Model)
+	total := CountMessages(t, in.Messages)
+	if len(in.Tools) > 0 {
+		tools, _ := sonic.ConfigDefault.MarshalToString(in.Tools)
+		total += t.Count(tools)
+	}
+	return total
+}
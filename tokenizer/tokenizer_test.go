@@ -0,0 +1,103 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jun3372/uniai/request"
+)
+
+// newTestRanks 构造一个包含全部单字节与少量合并规则的词表
+func newTestRanks(merges ...string) map[string]int {
+	ranks := make(map[string]int, 256+len(merges))
+	for i := 0; i < 256; i++ {
+		ranks[string([]byte{byte(i)})] = i
+	}
+	for i, m := range merges {
+		ranks[m] = 256 + i
+	}
+	return ranks
+}
+
+func Test_Split(t *testing.T) {
+	bpe, err := NewBPE(newTestRanks(), Cl100kBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"hello world":         {"hello", " world"},
+		"hello   world":       {"hello", "  ", " world"},
+		"I'm 12345 ok!\n\nx ": {"I", "'m", " ", "123", "45", " ok", "!\n\n", "x", " "},
+		"你好，世界":               {"你好", "，世界"},
+	}
+	for text, want := range tests {
+		if got := bpe.split(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("split(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func Test_BPE(t *testing.T) {
+	bpe, err := NewBPE(newTestRanks("he", "ll", "hell", "hello", " w"), Cl100kBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := bpe.Encode("hello world")
+	if want := []int{259, 260, 'o', 'r', 'l', 'd'}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("Encode = %v, want %v", ids, want)
+	}
+	if got := bpe.Decode(ids); got != "hello world" {
+		t.Fatalf("Decode = %q", got)
+	}
+}
+
+func Test_LoadDir(t *testing.T) {
+	var sb strings.Builder
+	for token, rank := range newTestRanks("ab") {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), rank)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "qwen.tiktoken"), []byte(sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadDir(dir)
+	if err != nil || !reflect.DeepEqual(loaded, []string{Qwen}) {
+		t.Fatalf("LoadDir = %v, %v", loaded, err)
+	}
+	defer func() {
+		mu.Lock()
+		delete(encodings, Qwen)
+		mu.Unlock()
+	}()
+
+	if n := ForModel("qwen-max").Count("abab"); n != 2 {
+		t.Fatalf("Count = %d, want 2", n)
+	}
+}
+
+func Test_CountRequest(t *testing.T) {
+	if n := (Estimator{}).Count("你好 hello"); n != 4 {
+		t.Fatalf("Estimator.Count = %d, want 4", n)
+	}
+
+	in := request.Request{
+		Model: "unknown-model",
+		Messages: []request.Messages{
+			request.NewSystemMessage("be brief"),
+			request.NewUserMessage("你好"),
+		},
+	}
+	// 回复起始 3 + 两条消息各 3 + system(2) + "be brief"(2) + user(1) + "你好"(2)
+	if n := CountRequest(in); n != 3+6+2+2+1+2 {
+		t.Fatalf("CountRequest = %d", n)
+	}
+}
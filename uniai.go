@@ -2,19 +2,32 @@ package uniai
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/jun3372/uniai/catalog"
 	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/internal/ark"
+	"github.com/jun3372/uniai/internal/baidubce"
+	"github.com/jun3372/uniai/internal/hunyuan"
 	"github.com/jun3372/uniai/internal/openai"
+	"github.com/jun3372/uniai/internal/tongyi"
 	"github.com/jun3372/uniai/internal/xfyun"
+	"github.com/jun3372/uniai/internal/zhipu"
 	"github.com/jun3372/uniai/request"
 	"github.com/jun3372/uniai/response"
 )
 
 // Iuniai 接口定义了UI nai需要实现的方法
 type iuniai interface {
-	Completions(ctx context.Context, in request.Request) (chan response.Response, error) // 该方法用于处理请求并返回补全结果
+	Completions(ctx context.Context, in request.Request) (chan response.Response, error)              // 该方法用于处理请求并返回补全结果
+	Stream(ctx context.Context, in request.Request) (*response.Stream, error)                         // 该方法与 Completions 相同，但返回可以提前关闭的流
+	Embeddings(ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) // 该方法用于获取文本的向量
+	Images() Images                                                                                   // 该方法用于获取图像生成能力
+	Audio() Audio                                                                                     // 该方法用于获取语音识别与语音合成能力
+	ListModels(ctx context.Context) (*response.ModelList, error)                                      // 该方法用于获取服务提供的模型列表
 }
 
 // uniai 结构体实现了Iuniai接口
@@ -22,6 +35,9 @@ type uniai struct {
 	opts   *client.Options // 客户端选项配置
 	client client.IClient  // 客户端接口实例
 	onces  sync.Once       // 用于确保某些操作只执行一次
+
+	mu      sync.Mutex        // 保护 targets
+	targets map[string]*uniai // 以别名为键，别名对应的服务商的实例
 }
 
 // New 函数用于创建一个新的 uniai 实例
@@ -36,12 +52,150 @@ func New(opts ...client.Option) iuniai {
 	return resp // 返回配置好的 uniai 实例
 }
 
+// Completions 方法用于获取补全结果。
+// 请求的模型是 client.WithRoute 设置的别名时，请求会被转发给别名对应的服务商与模型。
+// 配置了校验器时会先校验请求参数，校验失败的请求不会被发送。
+// 当请求指定了 response_format 而客户端不支持该格式时，会以提示词的方式模拟输出格式。
 func (u *uniai) Completions(ctx context.Context, in request.Request) (chan response.Response, error) {
+	target, route, ok := u.route(in.Model)
+	if ok {
+		in.Model = route.Model
+		in = withDefaults(in, route.Params)
+	}
+	return target.complete(ctx, in)
+}
+
+// complete 校验并发送补全请求
+func (u *uniai) complete(ctx context.Context, in request.Request) (chan response.Response, error) {
+	if u.opts.Validator != nil {
+		if err := u.opts.Validator.Validate(in); err != nil {
+			return nil, err
+		}
+	}
+
+	c := u.getClient()
+	if in.ResponseFormat != nil && !supportsResponseFormat(c, in.ResponseFormat.Type) {
+		in = request.EmulateResponseFormat(in, supportsResponseFormat(c, request.ResponseFormatJSONObject))
+	}
+
+	return c.Completions(*u.opts, ctx, in)
+}
+
+// Stream 方法与 Completions 相同，但返回一个 response.Stream。
+// 调用方提前停止读取时应调用 Stream.Close，以立即释放连接与输出协程。
+func (u *uniai) Stream(ctx context.Context, in request.Request) (*response.Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	out, err := u.Completions(ctx, in)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return response.NewStream(out, cancel), nil
+}
+
+// Embeddings 方法用于获取文本的向量。
+// 请求的模型是 client.WithRoute 设置的别名时，请求会被转发给别名对应的服务商与模型。
+// 文本条数超过服务商的单次上限时会自动分批请求，并按原始顺序合并结果与用量统计。
+func (u *uniai) Embeddings(ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	target, route, ok := u.route(in.Model)
+	if ok {
+		in.Model = route.Model
+	}
+	return target.embed(ctx, in)
+}
+
+// embed 分批发送向量化请求并合并结果
+func (u *uniai) embed(ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	if len(in.Input) == 0 {
+		return nil, errorx.InvalidInput
+	}
+
+	c := u.getClient()
+	size := in.BatchSize
+	if sizer, ok := c.(client.EmbeddingBatchSizer); ok && size <= 0 {
+		size = sizer.EmbeddingBatchSize(in.Model)
+	}
+	if size <= 0 || size > len(in.Input) {
+		size = len(in.Input)
+	}
+
+	out := &response.EmbeddingResponse{Object: "list", Model: in.Model, Data: make([]response.Embedding, 0, len(in.Input))}
+	for start := 0; start < len(in.Input); start += size {
+		batch := in
+		batch.Input = in.Input[start:min(start+size, len(in.Input))]
+
+		resp, err := c.Embeddings(*u.opts, ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Model != "" {
+			out.Model = resp.Model
+		}
+		for _, item := range resp.Data {
+			item.Index += start
+			out.Data = append(out.Data, item)
+		}
+		if resp.Usage != nil {
+			if out.Usage == nil {
+				out.Usage = &response.Usage{}
+			}
+			out.Usage.PromptTokens += resp.Usage.PromptTokens
+			out.Usage.CompletionTokens += resp.Usage.CompletionTokens
+			out.Usage.TotalTokens += resp.Usage.TotalTokens
+		}
+	}
+
+	sort.SliceStable(out.Data, func(i, j int) bool { return out.Data[i].Index < out.Data[j].Index })
+	return out, nil
+}
+
+// Provider 方法返回客户端的服务商类型，未设置时为 openai
+func (u *uniai) Provider() string {
+	if u.opts.Type == "" {
+		return client.OpenAI
+	}
+	return strings.ToLower(u.opts.Type)
+}
+
+// ListModels 方法用于获取服务提供的模型列表。
+// 服务商没有模型列表接口时，返回内置模型目录中属于该服务商的模型。
+func (u *uniai) ListModels(ctx context.Context) (*response.ModelList, error) {
+	if lister, ok := u.getClient().(client.ModelLister); ok {
+		return lister.ListModels(*u.opts, ctx)
+	}
+
+	provider := u.Provider()
+
+	out := &response.ModelList{Object: "list", Data: []response.Model{}}
+	for _, m := range catalog.Default.Models(catalog.ByProvider(provider)) {
+		out.Data = append(out.Data, response.Model{ID: m.Name, Object: "model", OwnedBy: provider})
+	}
+	return out, nil
+}
+
+// getClient 根据选项中的类型返回对应的客户端，客户端只会创建一次。
+func (u *uniai) getClient() client.IClient {
 	if u.client == nil {
 		u.onces.Do(func() {
+			if u.opts.Client != nil {
+				u.client = u.opts.Client
+				return
+			}
+
 			switch strings.ToLower(u.opts.Type) {
 			case client.Xfyun:
 				u.client = xfyun.NewClient()
+			case client.Zhipu:
+				u.client = zhipu.NewClient()
+			case client.Hunyuan:
+				u.client = hunyuan.NewClient()
+			case client.Ark:
+				u.client = ark.NewClient()
+			case client.Baidubce:
+				u.client = baidubce.NewClient()
+			case client.Tongyi:
+				u.client = tongyi.NewClient()
 			case client.OpenAI, "":
 				u.client = openai.NewClient()
 			default:
@@ -50,5 +204,15 @@ func (u *uniai) Completions(ctx context.Context, in request.Request) (chan respo
 		})
 	}
 
-	return u.client.Completions(*u.opts, ctx, in)
+	return u.client
+}
+
+// supportsResponseFormat 判断客户端是否原生支持指定的输出格式
+func supportsResponseFormat(c client.IClient, formatType string) bool {
+	if formatType == request.ResponseFormatText {
+		return true
+	}
+
+	s, ok := c.(client.ResponseFormatSupporter)
+	return ok && s.SupportsResponseFormat(formatType)
 }
@@ -0,0 +1,157 @@
+package uniaitest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// ErrNoReply 表示预设的回复已经用完
+var ErrNoReply = errors.New("uniaitest: no more replies")
+
+// Client 是一个可编排的内存客户端，实现了 client.IClient 接口。
+// 每次调用 Completions 按顺序消费一个预设回复，并记录收到的请求，可以通过 client.WithClient 注入到 uniai 中。
+type Client struct {
+	mu         sync.Mutex
+	replies    []Reply
+	requests   []request.Request
+	embeddings []request.EmbeddingRequest
+	seq        int
+
+	// Embed 生成文本的向量，为 nil 时返回以文本字节数为唯一元素的向量
+	Embed func(text string) []float32
+}
+
+// NewClient 创建一个按顺序返回指定回复的内存客户端
+func NewClient(replies ...Reply) *Client {
+	return &Client{replies: replies}
+}
+
+// Add 追加预设回复
+func (c *Client) Add(replies ...Reply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replies = append(c.replies, replies...)
+}
+
+// Requests 返回收到的补全请求
+func (c *Client) Requests() []request.Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]request.Request(nil), c.requests...)
+}
+
+// EmbeddingRequests 返回收到的向量化请求
+func (c *Client) EmbeddingRequests() []request.EmbeddingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]request.EmbeddingRequest(nil), c.embeddings...)
+}
+
+// Remaining 返回尚未消费的回复数量
+func (c *Client) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.replies)
+}
+
+// next 记录请求并取出下一个回复
+func (c *Client) next(in request.Request) (Reply, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, in)
+	if len(c.replies) == 0 {
+		return Reply{}, "", ErrNoReply
+	}
+
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	c.seq++
+	return reply, fmt.Sprintf("chatcmpl-%d", c.seq), nil
+}
+
+// Completions 按顺序返回预设的回复，ctx 被取消时停止输出并关闭通道
+func (c *Client) Completions(opt client.Options, ctx context.Context, in request.Request) (chan response.Response, error) {
+	reply, id, err := c.next(in)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+
+	model := reply.Model
+	if model == "" {
+		model = in.Model
+	}
+
+	chunks := []response.Response{reply.message(id, model)}
+	if in.Stream {
+		chunks = reply.chunks(id, model)
+	}
+
+	out := make(chan response.Response, in.ChannelMaxLength)
+	go func() {
+		defer close(out)
+		for _, chunk := range chunks {
+			if reply.Delay > 0 {
+				select {
+				case <-time.After(reply.Delay):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		var err error
+		switch {
+		case !in.Stream:
+		case reply.StreamErr != nil:
+			err = reply.StreamErr
+		case reply.Truncate:
+			err = errorx.StreamTruncated
+		}
+		if err != nil {
+			select {
+			case out <- response.Response{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Embeddings 返回由 Embed 生成的向量
+func (c *Client) Embeddings(opt client.Options, ctx context.Context, in request.EmbeddingRequest) (*response.EmbeddingResponse, error) {
+	c.mu.Lock()
+	c.embeddings = append(c.embeddings, in)
+	embed := c.Embed
+	c.mu.Unlock()
+
+	if embed == nil {
+		embed = func(text string) []float32 { return []float32{float32(len(text))} }
+	}
+
+	out := &response.EmbeddingResponse{Object: "list", Model: in.Model, Usage: &response.Usage{}}
+	for i, text := range in.Input {
+		out.Data = append(out.Data, response.Embedding{Object: "embedding", Index: i, Embedding: embed(text)})
+		out.Usage.PromptTokens += len(text)
+	}
+	out.Usage.TotalTokens = out.Usage.PromptTokens
+	return out, nil
+}
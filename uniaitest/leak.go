@@ -0,0 +1,63 @@
+package uniaitest
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leakTimeout 是等待协程退出的最长时间
+const leakTimeout = 2 * time.Second
+
+// VerifyNoLeaks 记录当前存在的协程，并在测试结束时检查是否有新的协程残留，残留的协程会连同调用栈一起报告。
+// 它应在测试开始时调用，以便在模拟服务等其他清理函数之后执行；并行运行的测试会互相干扰，不能使用该函数。
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+	before := goroutines()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(leakTimeout)
+		for {
+			var leaked []string
+			for id, stack := range goroutines() {
+				if _, ok := before[id]; !ok {
+					leaked = append(leaked, stack)
+				}
+			}
+			if len(leaked) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("uniaitest: %d goroutines leaked:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// goroutines 返回当前所有协程的调用栈，以协程编号为键
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	out := make(map[string]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		// 每个调用栈以 "goroutine 18 [running]:" 开头
+		header, _, _ := strings.Cut(string(stack), "\n")
+		fields := strings.Fields(header)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		out[fields[1]] = string(stack)
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+// Package uniaitest 提供测试 uniai 相关代码的工具：
+// 可编排的内存客户端 Client，以及模拟 OpenAI 兼容、讯飞星火与百度千帆接口格式的 HTTP 服务 Server。
+package uniaitest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jun3372/uniai/response"
+)
+
+// Reply 描述一次预设的模型回复，内存客户端与模拟服务都按照 Reply 生成响应。
+// 流式请求时 Content 中的每一段作为一个分片返回，非流式请求时拼接为一条完整的消息。
+type Reply struct {
+	Content          []string            // 回复内容的分段
+	ReasoningContent string              // 推理模型的思考过程
+	ToolCalls        []response.ToolCall // 工具调用
+	FinishReason     string              // 结束原因，默认为 stop，包含工具调用时默认为 tool_calls
+	Usage            *response.Usage     // 用量统计，附加在最后一个分片上
+	Model            string              // 响应中的模型名称，默认使用请求中的模型
+	Delay            time.Duration       // 每个分片之前的等待时间，非流式请求时为响应之前的等待时间，用于模拟慢速响应与超时
+	Err              error               // 请求返回的错误，模拟服务会将 *errorx.APIError 转换为对应的状态码与响应体
+	Raw              []string            // 模拟服务在流式响应的内容分片之前原样发送的 data 内容，用于模拟格式错误的分片
+	RawSSE           []string            // 模拟服务在 Raw 之前原样写入的 SSE 文本，用于模拟注释、事件名称与不规范的换行
+	OmitDone         bool                // 模拟服务的流式响应不发送 [DONE] 结束标记
+	Truncate         bool                // 流式响应的最后一个分片不携带结束原因，模拟服务不发送 [DONE]，内存客户端以 errorx.StreamTruncated 结束
+	StreamErr        error               // 流式响应在内容分片之后返回的错误，模拟服务以 event: error 事件发送，内存客户端以 Response.Err 返回
+}
+
+// Text 返回内容为指定文本的回复，多个参数在流式请求中作为多个分片返回
+func Text(content ...string) Reply {
+	return Reply{Content: content}
+}
+
+// ToolCalls 返回发起工具调用的回复，Index 为 0 的调用按位置编号，未设置 Type 的调用视为 function。
+// calls 会被复制，调用方的切片不会被修改。
+func ToolCalls(calls ...response.ToolCall) Reply {
+	calls = append([]response.ToolCall(nil), calls...)
+	for i := range calls {
+		if calls[i].Index == 0 {
+			calls[i].Index = i
+		}
+		if calls[i].Type == "" {
+			calls[i].Type = "function"
+		}
+	}
+	return Reply{ToolCalls: calls}
+}
+
+// Error 返回请求失败的回复
+func Error(err error) Reply {
+	return Reply{Err: err}
+}
+
+// finishReason 返回回复的结束原因
+func (r Reply) finishReason() string {
+	switch {
+	case r.FinishReason != "":
+		return r.FinishReason
+	case len(r.ToolCalls) > 0:
+		return "tool_calls"
+	default:
+		return "stop"
+	}
+}
+
+// content 返回完整的回复内容
+func (r Reply) content() string {
+	return strings.Join(r.Content, "")
+}
+
+// message 返回非流式请求的完整响应
+func (r Reply) message(id, model string) response.Response {
+	return response.Response{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: int(time.Now().Unix()),
+		Model:   model,
+		Usage:   r.Usage,
+		Choices: []response.Choices{{
+			FinishReason: r.finishReason(),
+			Message:      &response.Message{Role: "assistant", Content: r.content(), ReasoningContent: r.ReasoningContent, ToolCalls: r.ToolCalls},
+		}},
+	}
+}
+
+// chunks 返回流式请求的分片，第一个分片携带角色，最后一个分片携带结束原因与用量统计
+func (r Reply) chunks(id, model string) []response.Response {
+	created := int(time.Now().Unix())
+	chunk := func(delta response.Delta) response.Response {
+		return response.Response{ID: id, Object: "chat.completion.chunk", Created: created, Model: model, Choices: []response.Choices{{Delta: &delta}}}
+	}
+
+	var out []response.Response
+	if r.ReasoningContent != "" {
+		out = append(out, chunk(response.Delta{ReasoningContent: r.ReasoningContent}))
+	}
+	for _, part := range r.Content {
+		out = append(out, chunk(response.Delta{Content: part}))
+	}
+	for _, call := range r.ToolCalls {
+		out = append(out, chunk(response.Delta{ToolCalls: []response.ToolCall{call}}))
+	}
+	if len(out) == 0 {
+		out = append(out, chunk(response.Delta{}))
+	}
+
+	out[0].Choices[0].Delta.Role = "assistant"
+	last := &out[len(out)-1]
+	if !r.Truncate {
+		last.Choices[0].FinishReason = r.finishReason()
+	}
+	last.Usage = r.Usage
+	return out
+}
@@ -0,0 +1,362 @@
+package uniaitest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// 以下为模拟服务支持的接口格式
+const (
+	FormatOpenAI  = "openai"  // OpenAI 兼容格式，流式响应以 data: [DONE] 结束
+	FormatSpark   = "spark"   // 讯飞星火格式，在 OpenAI 格式的基础上每个分片携带 code、message 与 sid
+	FormatQianfan = "qianfan" // 百度千帆格式，回复位于 result 字段，流式响应以 is_end 标记结束，错误以 200 状态码返回
+	FormatTC3     = "tc3"     // 腾讯云 API 3.0 格式（如腾讯混元），字段为大驼峰，流式响应没有 [DONE]，错误以 200 状态码返回
+)
+
+// Recorded 是模拟服务收到的一次请求
+type Recorded struct {
+	Method string      // 请求方法
+	Path   string      // 请求路径
+	Query  url.Values  // 查询参数
+	Header http.Header // 请求头
+	Body   []byte      // 请求体
+}
+
+// Request 将请求体解码为补全请求，解码失败时测试立即失败
+func (r Recorded) Request(t testing.TB) request.Request {
+	t.Helper()
+	var in request.Request
+	if err := sonic.ConfigDefault.Unmarshal(r.Body, &in); err != nil {
+		t.Fatalf("uniaitest: decode request body: %v", err)
+	}
+	return in
+}
+
+// AssertHeader 断言请求头的值
+func (r Recorded) AssertHeader(t testing.TB, key, want string) {
+	t.Helper()
+	if got := r.Header.Get(key); got != want {
+		t.Errorf("uniaitest: header %s = %q, want %q", key, got, want)
+	}
+}
+
+// Server 是模拟服务商接口的 HTTP 服务，按顺序使用预设回复响应补全请求，并记录收到的所有请求。
+type Server struct {
+	*httptest.Server
+
+	format   string
+	mu       sync.Mutex
+	replies  []Reply
+	requests []Recorded
+	seq      int
+}
+
+// NewServer 启动一个指定格式的模拟服务，测试结束时自动关闭
+func NewServer(t testing.TB, format string, replies ...Reply) *Server {
+	s := &Server{format: format, replies: replies}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Add 追加预设回复
+func (s *Server) Add(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = append(s.replies, replies...)
+}
+
+// Requests 返回收到的所有请求
+func (s *Server) Requests() []Recorded {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Recorded(nil), s.requests...)
+}
+
+// Request 返回第 i 次收到的请求，请求不存在时测试立即失败
+func (s *Server) Request(t testing.TB, i int) Recorded {
+	t.Helper()
+	requests := s.Requests()
+	if i < 0 || i >= len(requests) {
+		t.Fatalf("uniaitest: request %d not found, got %d requests", i, len(requests))
+	}
+	return requests[i]
+}
+
+// AssertRequestCount 断言收到的请求数量
+func (s *Server) AssertRequestCount(t testing.TB, want int) {
+	t.Helper()
+	if got := len(s.Requests()); got != want {
+		t.Errorf("uniaitest: got %d requests, want %d", got, want)
+	}
+}
+
+// handle 记录请求并按路径分发
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, Recorded{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	s.mu.Unlock()
+
+	if s.format == FormatTC3 && r.Header.Get("X-TC-Action") == tc3EmbeddingAction {
+		tc3Embeddings(w, body)
+		return
+	}
+	if strings.Contains(r.URL.Path, "embeddings") {
+		s.embeddings(w, body)
+		return
+	}
+	s.completions(w, r, body)
+}
+
+// next 取出下一个回复
+func (s *Server) next() (Reply, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.replies) == 0 {
+		return Reply{}, "", ErrNoReply
+	}
+
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	s.seq++
+	return reply, fmt.Sprintf("chatcmpl-%d", s.seq), nil
+}
+
+// completions 响应补全请求
+func (s *Server) completions(w http.ResponseWriter, r *http.Request, body []byte) {
+	var in request.Request
+	_ = sonic.ConfigDefault.Unmarshal(body, &in)
+
+	reply, id, err := s.next()
+	if err == nil {
+		err = reply.Err
+	}
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	model := reply.Model
+	if model == "" {
+		model = in.Model
+	}
+
+	if !in.Stream {
+		if reply.Delay > 0 {
+			select {
+			case <-time.After(reply.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		var out any = reply.message(id, model)
+		switch s.format {
+		case FormatQianfan:
+			out = qianfanChunk(reply.message(id, model), 0, true)
+		case FormatTC3:
+			out = map[string]any{"Response": tc3Response(reply.message(id, model))}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		data, _ := sonic.ConfigDefault.Marshal(out)
+		w.Write(data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	// 与真实服务一样先发送响应头，使客户端在第一个分片到达之前就开始读取流
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for _, raw := range reply.RawSSE {
+		io.WriteString(w, raw)
+	}
+	for _, raw := range reply.Raw {
+		fmt.Fprintf(w, "data: %s\n\n", raw)
+	}
+
+	chunks := reply.chunks(id, model)
+	for i, chunk := range chunks {
+		if reply.Delay > 0 {
+			select {
+			case <-time.After(reply.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		var out any = chunk
+		switch s.format {
+		case FormatSpark:
+			out = sparkChunk{Response: chunk, Code: 0, Message: "Success", Sid: id}
+		case FormatQianfan:
+			out = qianfanChunk(chunk, i, i == len(chunks)-1)
+		case FormatTC3:
+			out = tc3Response(chunk)
+		}
+
+		data, _ := sonic.ConfigDefault.Marshal(out)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	if reply.StreamErr != nil {
+		s.writeStreamError(w, reply.StreamErr, id)
+		return
+	}
+	if s.format != FormatQianfan && s.format != FormatTC3 && !reply.OmitDone && !reply.Truncate {
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}
+}
+
+// embeddings 响应向量化请求，每条文本的向量为以文本字节数为唯一元素的向量
+func (s *Server) embeddings(w http.ResponseWriter, body []byte) {
+	var in request.EmbeddingRequest
+	_ = sonic.ConfigDefault.Unmarshal(body, &in)
+
+	out := response.EmbeddingResponse{Object: "list", Model: in.Model, Usage: &response.Usage{}}
+	for i, text := range in.Input {
+		out.Data = append(out.Data, response.Embedding{Object: "embedding", Index: i, Embedding: response.Vector{float32(len(text))}})
+		out.Usage.PromptTokens += len(text)
+	}
+	out.Usage.TotalTokens = out.Usage.PromptTokens
+
+	w.Header().Set("Content-Type", "application/json")
+	data, _ := sonic.ConfigDefault.Marshal(out)
+	w.Write(data)
+}
+
+// writeError 按服务商的格式返回错误
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	status, code, message := http.StatusInternalServerError, "", err.Error()
+	var apiErr *errorx.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode > 0 {
+			status = apiErr.StatusCode
+		}
+		code, message = apiErr.Code, apiErr.Message
+		if apiErr.Body != "" {
+			w.WriteHeader(status)
+			io.WriteString(w, apiErr.Body)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	data := errorBody(err)
+	switch s.format {
+	case FormatQianfan:
+		// 千帆的错误以 200 状态码返回，错误码为数字。
+		n, _ := strconv.Atoi(code)
+		if n == 0 {
+			n = status
+		}
+		status = http.StatusOK
+		data, _ = sonic.ConfigDefault.Marshal(map[string]any{"error_code": n, "error_msg": message})
+	case FormatTC3:
+		// 腾讯云的错误以 200 状态码返回，错误信息位于 Response.Error 中。
+		data, _ = sonic.ConfigDefault.Marshal(map[string]any{"Response": map[string]any{"Error": tc3Error(err, status), "RequestId": "uniaitest"}})
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeStreamError 在流式响应中返回错误，星火以状态码不为 0 的分片返回，腾讯云以携带 Error 的分片返回，其余格式使用 error 事件
+func (s *Server) writeStreamError(w http.ResponseWriter, err error, id string) {
+	if s.format == FormatTC3 {
+		data, _ := sonic.ConfigDefault.Marshal(map[string]any{"Error": tc3Error(err, http.StatusOK), "RequestId": id})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		return
+	}
+	if s.format != FormatSpark {
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", errorBody(err))
+		return
+	}
+
+	code, message := 10000, err.Error()
+	var apiErr *errorx.APIError
+	if errors.As(err, &apiErr) {
+		if n, _ := strconv.Atoi(apiErr.Code); n != 0 {
+			code = n
+		}
+		message = apiErr.Message
+	}
+	data, _ := sonic.ConfigDefault.Marshal(sparkChunk{Code: code, Message: message, Sid: id})
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+// errorBody 返回 OpenAI 格式的错误响应体
+func errorBody(err error) []byte {
+	code, message := "", err.Error()
+	var apiErr *errorx.APIError
+	if errors.As(err, &apiErr) {
+		code, message = apiErr.Code, apiErr.Message
+	}
+	data, _ := sonic.ConfigDefault.Marshal(map[string]any{"error": map[string]any{"code": code, "message": message, "type": "uniaitest"}})
+	return data
+}
+
+// sparkChunk 是讯飞星火的流式分片，在 OpenAI 格式的基础上携带状态码与会话标识
+type sparkChunk struct {
+	response.Response
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Sid     string `json:"sid"`
+}
+
+// qianfanChunk 将响应转换为千帆的格式
+func qianfanChunk(resp response.Response, sentence int, end bool) map[string]any {
+	var result, finish string
+	if len(resp.Choices) > 0 {
+		c := resp.Choices[0]
+		finish = c.FinishReason
+		if c.Message != nil {
+			result = c.Message.Content
+		}
+		if c.Delta != nil {
+			result = c.Delta.Content
+		}
+	}
+	if finish == "stop" {
+		finish = "normal"
+	}
+
+	out := map[string]any{
+		"id":            resp.ID,
+		"object":        "chat.completion",
+		"created":       resp.Created,
+		"sentence_id":   sentence,
+		"is_end":        end,
+		"is_truncated":  false,
+		"result":        result,
+		"finish_reason": finish,
+	}
+	if resp.Usage != nil {
+		out["usage"] = resp.Usage
+	}
+	return out
+}
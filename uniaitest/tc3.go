@@ -0,0 +1,103 @@
+package uniaitest
+
+import (
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/response"
+)
+
+// tc3EmbeddingAction 是腾讯云 API 3.0 向量化接口的 Action 名称
+const tc3EmbeddingAction = "GetEmbedding"
+
+// tc3Response 将响应转换为腾讯云 API 3.0 的大驼峰格式，流式分片直接使用该结构，非流式响应包裹在 Response 字段中
+func tc3Response(resp response.Response) map[string]any {
+	choices := make([]map[string]any, 0, len(resp.Choices))
+	for _, c := range resp.Choices {
+		choice := map[string]any{"FinishReason": c.FinishReason}
+		if c.Delta != nil {
+			choice["Delta"] = tc3Message(c.Delta.Role, c.Delta.Content, c.Delta.ReasoningContent, c.Delta.ToolCalls)
+		}
+		if c.Message != nil {
+			choice["Message"] = tc3Message(c.Message.Role, c.Message.Content, c.Message.ReasoningContent, c.Message.ToolCalls)
+		}
+		choices = append(choices, choice)
+	}
+
+	out := map[string]any{"Id": resp.ID, "Created": resp.Created, "Choices": choices, "RequestId": resp.ID}
+	if resp.Usage != nil {
+		out["Usage"] = map[string]any{
+			"PromptTokens":     resp.Usage.PromptTokens,
+			"CompletionTokens": resp.Usage.CompletionTokens,
+			"TotalTokens":      resp.Usage.TotalTokens,
+		}
+	}
+	return out
+}
+
+// tc3Message 返回大驼峰格式的消息
+func tc3Message(role, content, reasoning string, calls []response.ToolCall) map[string]any {
+	out := map[string]any{"Role": role, "Content": content}
+	if reasoning != "" {
+		out["ReasoningContent"] = reasoning
+	}
+	if len(calls) > 0 {
+		items := make([]map[string]any, 0, len(calls))
+		for _, call := range calls {
+			items = append(items, map[string]any{
+				"Id":       call.ID,
+				"Type":     call.Type,
+				"Index":    call.Index,
+				"Function": map[string]any{"Name": call.Function.Name, "Arguments": call.Function.Arguments},
+			})
+		}
+		out["ToolCalls"] = items
+	}
+	return out
+}
+
+// tc3Error 返回腾讯云 API 3.0 的错误信息，未设置错误码时按状态码选择对应的公共错误码
+func tc3Error(err error, status int) map[string]any {
+	code, message := "", err.Error()
+	var apiErr *errorx.APIError
+	if errors.As(err, &apiErr) {
+		code, message = apiErr.Code, apiErr.Message
+	}
+	if code == "" {
+		switch {
+		case status == http.StatusBadRequest:
+			code = "InvalidParameter"
+		case status == http.StatusUnauthorized:
+			code = "AuthFailure.SignatureFailure"
+		case status == http.StatusForbidden:
+			code = "UnauthorizedOperation"
+		case status == http.StatusNotFound:
+			code = "ResourceNotFound"
+		case status == http.StatusTooManyRequests:
+			code = "RequestLimitExceeded"
+		default:
+			code = "InternalError"
+		}
+	}
+	return map[string]any{"Code": code, "Message": message}
+}
+
+// tc3Embeddings 响应腾讯云 API 3.0 的向量化请求，混元单次只接受一条文本
+func tc3Embeddings(w http.ResponseWriter, body []byte) {
+	var in struct {
+		Input string `json:"Input"`
+	}
+	_ = sonic.ConfigDefault.Unmarshal(body, &in)
+
+	n := len(in.Input)
+	out := map[string]any{
+		"Data":  []map[string]any{{"Embedding": response.Vector{float32(n)}, "Index": 0, "Object": "embedding"}},
+		"Usage": map[string]any{"PromptTokens": n, "TotalTokens": n},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	data, _ := sonic.ConfigDefault.Marshal(map[string]any{"Response": out})
+	w.Write(data)
+}
@@ -0,0 +1,194 @@
+package uniaitest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jun3372/uniai"
+	"github.com/jun3372/uniai/client"
+	"github.com/jun3372/uniai/errorx"
+	"github.com/jun3372/uniai/request"
+	"github.com/jun3372/uniai/response"
+)
+
+// collect 读取通道中的所有分片并归并
+func collect(out chan response.Response) response.Message {
+	acc := response.NewAccumulator()
+	for item := range out {
+		acc.Add(item)
+	}
+	return acc.Message()
+}
+
+func newRequest(stream bool) request.Request {
+	return *request.NewRequest(
+		request.WithModel("test-model"),
+		request.WithStream(stream),
+		request.WithMessages([]request.Messages{request.NewUserMessage("hi")}),
+	)
+}
+
+func Test_Client(t *testing.T) {
+	mock := NewClient(
+		Text("你", "好"),
+		ToolCalls(response.ToolCall{ID: "call_1", Function: response.FunctionCall{Name: "f", Arguments: "{}"}}),
+		Error(errorx.RateLimited),
+		Reply{Content: []string{"slow"}, Delay: time.Second},
+	)
+	ai := uniai.New(client.WithClient(mock))
+
+	out, err := ai.Completions(context.Background(), newRequest(true))
+	if err != nil || collect(out).Content != "你好" {
+		t.Fatalf("stream reply: %v", err)
+	}
+
+	out, _ = ai.Completions(context.Background(), newRequest(false))
+	if msg := collect(out); len(msg.ToolCalls) != 1 || msg.ToolCalls[0].ID != "call_1" {
+		t.Fatalf("tool call reply: %+v", msg)
+	}
+
+	if _, err := ai.Completions(context.Background(), newRequest(false)); !errors.Is(err, errorx.RateLimited) {
+		t.Fatalf("err = %v, want RateLimited", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	out, _ = ai.Completions(ctx, newRequest(true))
+	if msg := collect(out); msg.Content != "" {
+		t.Fatalf("canceled reply should be empty, got %q", msg.Content)
+	}
+
+	if _, err := ai.Completions(context.Background(), newRequest(false)); !errors.Is(err, ErrNoReply) {
+		t.Fatalf("err = %v, want ErrNoReply", err)
+	}
+	if n := len(mock.Requests()); n != 5 {
+		t.Fatalf("recorded %d requests", n)
+	}
+}
+
+func Test_Server(t *testing.T) {
+	for _, tt := range []struct{ format, typ string }{{FormatOpenAI, client.OpenAI}, {FormatSpark, client.Xfyun}} {
+		t.Run(tt.format, func(t *testing.T) {
+			srv := NewServer(t, tt.format, Text("hello", " world"), Text("ok"))
+			ai := uniai.New(client.WithType(tt.typ), client.WithHost(srv.URL), client.AddHeader("Authorization", "Bearer sk"))
+
+			out, err := ai.Completions(context.Background(), newRequest(true))
+			if err != nil || collect(out).Content != "hello world" {
+				t.Fatalf("stream reply: %v", err)
+			}
+			out, err = ai.Completions(context.Background(), newRequest(false))
+			if err != nil || collect(out).Content != "ok" {
+				t.Fatalf("reply: %v", err)
+			}
+
+			srv.AssertRequestCount(t, 2)
+			first := srv.Request(t, 0)
+			first.AssertHeader(t, "Authorization", "Bearer sk")
+			if in := first.Request(t); in.Model != "test-model" || !in.Stream || in.Messages[0].Content != "hi" {
+				t.Fatalf("unexpected request: %+v", in)
+			}
+		})
+	}
+}
+
+func Test_Qianfan(t *testing.T) {
+	srv := NewServer(t, FormatQianfan, Text("文", "心"), Error(&errorx.APIError{Code: "18", Message: "Open api qps request limit reached"}))
+
+	resp, err := http.Post(srv.URL+"/rpc/2.0/ai_custom/v1/wenxinworkshop/chat/completions", "application/json", strings.NewReader(`{"stream":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"result":"心"`) || !strings.Contains(string(body), `"is_end":true`) || strings.Contains(string(body), "[DONE]") {
+		t.Fatalf("unexpected stream: %s", body)
+	}
+
+	resp, err = http.Post(srv.URL+"/chat", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"error_code":18`) {
+		t.Fatalf("unexpected error response: %d %s", resp.StatusCode, body)
+	}
+
+	ai := uniai.New(client.WithType(client.Baidubce), client.WithHost(srv.URL))
+	embeddings, err := ai.Embeddings(context.Background(), *request.NewEmbeddingRequest(request.WithEmbeddingModel("embedding-v1"), request.WithInput("ab", "abc")))
+	if err != nil || len(embeddings.Data) != 2 || embeddings.Data[1].Embedding[0] != 3 {
+		t.Fatalf("Embeddings = %+v, %v", embeddings, err)
+	}
+	if path := srv.Request(t, 2).Path; path != "/rpc/2.0/ai_custom/v1/wenxinworkshop/embeddings/embedding-v1" {
+		t.Fatalf("unexpected path: %s", path)
+	}
+}
+
+func Test_Raw(t *testing.T) {
+	srv := NewServer(t, FormatOpenAI, Reply{Content: []string{"ok"}, RawSSE: []string{": ping\n\n"}, Raw: []string{"{bad"}})
+	resp, err := http.Post(srv.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{"stream":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(string(body), ": ping\n\ndata: {bad\n\ndata: {") {
+		t.Fatalf("unexpected stream: %s", body)
+	}
+}
+
+func Test_ToolCalls(t *testing.T) {
+	calls := []response.ToolCall{{ID: "a"}, {ID: "b", Index: 5}, {ID: "c", Type: "web_search"}}
+	reply := ToolCalls(calls...)
+	if got := reply.ToolCalls; got[0].Index != 0 || got[1].Index != 5 || got[2].Index != 2 || got[0].Type != "function" || got[2].Type != "web_search" {
+		t.Fatalf("unexpected calls: %+v", got)
+	}
+	if calls[2].Index != 0 || calls[0].Type != "" {
+		t.Fatalf("caller's slice should not be modified: %+v", calls)
+	}
+}
+
+func Test_Stream(t *testing.T) {
+	VerifyNoLeaks(t)
+	srv := NewServer(t, FormatOpenAI, Reply{Content: strings.Split(strings.Repeat("x", 50), ""), Delay: 10 * time.Millisecond}, Text("ok"))
+	ai := uniai.New(client.WithType(client.OpenAI), client.WithHost(srv.URL))
+
+	in := newRequest(true)
+	in.ChannelMaxLength = 0
+	stream, err := ai.Stream(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := stream.Recv(); err != nil || resp.Choices[0].Delta.Content != "x" {
+		t.Fatalf("Recv = %+v, %v", resp, err)
+	}
+	stream.Close()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("Recv after Close = %v, want io.EOF", err)
+	}
+
+	stream, err = ai.Stream(context.Background(), newRequest(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	var content strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content.WriteString(resp.Choices[0].Delta.Content)
+	}
+	if content.String() != "ok" {
+		t.Fatalf("content = %q", content.String())
+	}
+}